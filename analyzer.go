@@ -1,9 +1,25 @@
+// Command goanalyzer checks, fixes and searches Go source with the rules registered in the cli package.
+//
+// The command registers no rules itself, so it reports only syntax errors and problems of suppression
+// directives. To run rules, build a command which imports the rule packages. The packages register
+// their rules with cli.RegisterRules from init. Rules can also be passed to cli.Main:
+//
+//	package main
+//
+//	import (
+//		"github.com/a6cexz/goanalyzer/cli"
+//		_ "example.com/rules"
+//	)
+//
+//	func main() {
+//		cli.Main()
+//	}
 package main
 
 import (
-	"fmt"
+	"github.com/a6cexz/goanalyzer/cli"
 )
 
 func main() {
-	fmt.Println("Analyzer...")
+	cli.Main()
 }
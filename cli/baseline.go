@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/a6cexz/goanalyzer/diag/baseline"
+)
+
+var baselineCommand = &command{
+	name:    "baseline",
+	usage:   "baseline write [flags] [packages]",
+	summary: "Baseline write records current diagnostics, so check reports only new diagnostics.",
+	run:     (*App).runBaseline,
+}
+
+func (a *App) runBaseline(args []string) int {
+	if len(args) > 0 && isHelpArg(args[0]) {
+		a.newFlagSet("baseline").Usage()
+		return ExitClean
+	}
+	if len(args) == 0 || args[0] != "write" {
+		if len(args) > 0 {
+			fmt.Fprintf(a.Stderr, "goanalyzer: unknown baseline command %q\n", args[0])
+		}
+		a.newFlagSet("baseline").Usage()
+		return ExitError
+	}
+
+	flags := a.newFlagSet("baseline")
+	output := flags.String("output", "", "baseline file, by default "+baseline.FileName+" in the module root")
+	configPath := flags.String("config", "", "configuration file, by default .goanalyzer.json or .goanalyzer.yaml in the module root")
+	tests := flags.Bool("tests", true, "analyze test files")
+	if code, ok := a.parseFlags(flags, args[1:]); !ok {
+		return code
+	}
+
+	s, err := a.newSession(*configPath, flags.Args(), *tests)
+	if err != nil {
+		return a.errorf("%v", err)
+	}
+
+	path := *output
+	if path == "" {
+		path = filepath.Join(s.config.Root, baseline.FileName)
+	}
+	path = resolvePath(s.dir, path)
+
+	b := &baseline.Baseline{Version: baseline.Version, Entries: []baseline.Entry{}, Root: filepath.Dir(path), Dir: s.dir}
+	b.Add(s.registry, s.analyze())
+	if err := b.Save(path); err != nil {
+		return a.errorf("%v", err)
+	}
+
+	count := 0
+	for _, e := range b.Entries {
+		count += e.Count
+	}
+	fmt.Fprintf(a.Stdout, "baseline with %d diagnostics written to %s\n", count, path)
+	return ExitClean
+}
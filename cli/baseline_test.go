@@ -0,0 +1,45 @@
+package cli_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/cli"
+	"github.com/a6cexz/goanalyzer/diag/baseline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseline(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{"a.go": "package m\n\nfunc f() {\n\tprintln()\n}\n"})
+	defer os.RemoveAll(dir)
+
+	code, stdout, stderr := runApp(dir, "baseline", "write")
+	assert.Equal(t, cli.ExitClean, code, stderr)
+	assert.Equal(t, "baseline with 1 diagnostics written to "+filepath.Join(dir, baseline.FileName)+"\n", stdout)
+
+	code, stdout, _ = runApp(dir, "check")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "no problems found (1 suppressed)\n", stdout)
+
+	code, _, _ = runApp(dir, "check", "-no-baseline")
+	assert.Equal(t, cli.ExitFindings, code)
+
+	// new diagnostic after shifted lines is reported
+	src := "package m\n\n// f prints\nfunc f() {\n\tprintln()\n\tprintln(1)\n}\n"
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0644))
+	code, stdout, _ = runApp(dir, "check")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Contains(t, stdout, "a.go:6:2: warning GA9000: avoid println")
+	assert.Contains(t, stdout, "1 warning (1 suppressed)")
+
+	code, _, _ = runApp(dir, "baseline", "write", "-output=other.json")
+	assert.Equal(t, cli.ExitClean, code)
+	code, _, _ = runApp(dir, "check", "-baseline=other.json")
+	assert.Equal(t, cli.ExitClean, code)
+
+	code, _, stderr = runApp(dir, "baseline", "read")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, `unknown baseline command "read"`)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/baseline"
+	"github.com/a6cexz/goanalyzer/diag/report"
+)
+
+var checkCommand = &command{
+	name:    "check",
+	usage:   "check [flags] [packages]",
+	summary: "Check runs the rules on Go packages, directories or files and reports diagnostics.",
+	run:     (*App).runCheck,
+}
+
+func (a *App) runCheck(args []string) int {
+	flags := a.newFlagSet("check")
+	format := flags.String("format", report.FormatText, "output format: "+strings.Join(report.GetFormats(), ", "))
+	output := flags.String("output", "", "write diagnostics to the file instead of stdout")
+	failOn := flags.String("fail-on", "warning", "lowest severity of diagnostics which fail the check: info, warning or error")
+	configPath := flags.String("config", "", "configuration file, by default .goanalyzer.json or .goanalyzer.yaml in the module root")
+	baselinePath := flags.String("baseline", "", "baseline file, by default "+baseline.FileName+" in the module root if it exists")
+	noBaseline := flags.Bool("no-baseline", false, "report diagnostics recorded in the baseline")
+	noColor := flags.Bool("no-color", false, "disable colors in text output")
+	tests := flags.Bool("tests", true, "analyze test files")
+	if code, ok := a.parseFlags(flags, args); !ok {
+		return code
+	}
+
+	failSeverity, ok := diag.ParseSeverity(*failOn)
+	if !ok || failSeverity == diag.SeverityHidden {
+		return a.errorf("invalid -fail-on value %q, expected info, warning or error", *failOn)
+	}
+
+	s, err := a.newSession(*configPath, flags.Args(), *tests)
+	if err != nil {
+		return a.errorf("%v", err)
+	}
+	diags := s.analyze()
+
+	if !*noBaseline {
+		b, err := s.loadBaseline(*baselinePath)
+		if err != nil {
+			return a.errorf("%v", err)
+		}
+		if b != nil {
+			b.Apply(s.registry, diags)
+		}
+	}
+
+	w := a.Stdout
+	if *output != "" {
+		f, err := os.Create(resolvePath(s.dir, *output))
+		if err != nil {
+			return a.errorf("%v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := s.render(w, *format, *noColor, diags); err != nil {
+		return a.errorf("%v", err)
+	}
+
+	for _, d := range diags {
+		if !d.IsSuppressed() && d.Severity >= failSeverity {
+			return ExitFindings
+		}
+	}
+	return ExitClean
+}
+
+// loadBaseline loads the baseline file, if path is empty the default baseline file is loaded if it exists
+func (s *session) loadBaseline(path string) (*baseline.Baseline, error) {
+	if path != "" {
+		path = resolvePath(s.dir, path)
+	} else {
+		path = filepath.Join(s.config.Root, baseline.FileName)
+		if _, err := os.Stat(path); err != nil {
+			return nil, nil
+		}
+	}
+
+	b, err := baseline.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	b.Dir = s.dir
+	return b, nil
+}
+
+func (s *session) render(w io.Writer, format string, noColor bool, diags []diag.Diagnostic) error {
+	options := report.Options{
+		Sources:     s.registry,
+		Descriptors: s.getDescriptors(),
+		NoColor:     noColor,
+	}
+	r, err := report.NewRenderer(format, w, options)
+	if err != nil {
+		return err
+	}
+	if err := r.Render(diags); err != nil {
+		return fmt.Errorf("cannot write diagnostics: %v", err)
+	}
+	return nil
+}
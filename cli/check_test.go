@@ -0,0 +1,122 @@
+package cli_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+var checkFiles = map[string]string{
+	"a.go":          "package m\n\nfunc f() {\n\tprintln()\n}\n",
+	"cmd/tool/b.go": "package main\n\nfunc main() {\n\tprintln()\n}\n",
+	"clean/c.go":    "package clean\n",
+}
+
+func TestCheck(t *testing.T) {
+	dir := writeTestModule(t, checkFiles)
+	defer os.RemoveAll(dir)
+
+	code, stdout, stderr := runApp(dir, "check")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Equal(t, "", stderr)
+	assert.Equal(t, `a.go:4:2: warning GA9000: avoid println
+4 | 	println()
+  | 	^~~~~~~
+4 | 	println()
+  | 	--------- call
+
+cmd/tool/b.go:4:2: warning GA9000: avoid println
+4 | 	println()
+  | 	^~~~~~~
+4 | 	println()
+  | 	--------- call
+
+2 warnings
+`, stdout)
+
+	code, stdout, _ = runApp(dir, "check", "./clean")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "no problems found\n", stdout)
+
+	code, _, _ = runApp(dir, "check", "-fail-on=error", "./...")
+	assert.Equal(t, cli.ExitClean, code)
+
+	code, stdout, _ = runApp(dir, "check", "-format=checkstyle", "example.com/m/cmd/...")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Contains(t, stdout, `<file name="cmd/tool/b.go">`)
+	assert.NotContains(t, stdout, `a.go`)
+}
+
+func TestCheck_Output(t *testing.T) {
+	dir := writeTestModule(t, checkFiles)
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runApp(dir, "check", "-format=sarif", "-output=out.sarif", "a.go")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Equal(t, "", stdout)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "out.sarif"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"ruleId": "GA9000"`)
+	assert.Contains(t, string(content), `"id": "GA0001"`)
+}
+
+func TestCheck_Config(t *testing.T) {
+	files := map[string]string{
+		".goanalyzer.yaml": "rules:\n  no-println: error\noverrides:\n  - files: cmd\n    rules:\n      GA9000: off\n",
+	}
+	for name, content := range checkFiles {
+		files[name] = content
+	}
+	dir := writeTestModule(t, files)
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runApp(filepath.Join(dir, "cmd"), "check", "-fail-on=error", "-format=rdjson", "../...")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Contains(t, stdout, `"path": "`+filepath.Join(dir, "a.go")+`"`)
+	assert.Contains(t, stdout, `"severity": "ERROR"`)
+	assert.NotContains(t, stdout, `tool/b.go`)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".goanalyzer.yaml"), []byte("rules:\n  GA9999: off\n"), 0644))
+	code, _, stderr := runApp(dir, "check")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, ".goanalyzer.yaml: rules.GA9999: unknown rule")
+}
+
+func TestCheck_NoRules(t *testing.T) {
+	dir := writeTestModule(t, checkFiles)
+	defer os.RemoveAll(dir)
+
+	// without rules only syntax errors and suppression problems are reported
+	var stdout, stderr bytes.Buffer
+	app := &cli.App{Dir: dir, Stdout: &stdout, Stderr: &stderr}
+	assert.Equal(t, cli.ExitClean, app.Run([]string{"check"}))
+	assert.Equal(t, "no problems found\n", stdout.String())
+	assert.Equal(t, "", stderr.String())
+}
+
+func TestCheck_Errors(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{"a.go": "package m\n\nvar x = \n"})
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runApp(dir, "check", "-fail-on=error")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Contains(t, stdout, "a.go:4:1: error GA0001: expected operand, found 'EOF'")
+
+	code, _, stderr := runApp(dir, "check", "-fail-on=hidden")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, `invalid -fail-on value "hidden"`)
+
+	code, _, stderr = runApp(dir, "check", "-format=html")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, `unknown format "html"`)
+
+	code, _, stderr = runApp(dir, "check", "missing.go")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, "missing.go")
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+)
+
+// Exit codes of the commands
+const (
+	// ExitClean means that no diagnostics at or above the fail-on severity were reported
+	ExitClean = 0
+	// ExitFindings means that diagnostics at or above the fail-on severity were reported
+	ExitFindings = 1
+	// ExitError means that the command failed, e.g. because of invalid arguments or configuration
+	ExitError = 2
+)
+
+// App is the goanalyzer command line application which runs the rules
+type App struct {
+	Rules []analysis.Rule
+	// Dir is the directory patterns and paths of the arguments are relative to, the current directory if empty
+	Dir    string
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// command is the subcommand of the application
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(a *App, args []string) int
+}
+
+var commands = []*command{}
+
+func init() {
+	commands = append(commands, checkCommand, baselineCommand, dumpCommand, fixCommand, queryCommand)
+}
+
+var (
+	rulesMu         sync.Mutex
+	registeredRules = []analysis.Rule{}
+)
+
+// RegisterRules registers rules which Main runs in addition to the rules passed to it.
+// Rule packages call it from init, so a command runs their rules when it imports them:
+//
+//	import _ "example.com/rules"
+//
+//	func main() {
+//		cli.Main()
+//	}
+func RegisterRules(rules ...analysis.Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	registeredRules = append(registeredRules, rules...)
+}
+
+// GetRegisteredRules returns the registered rules in the order of registration
+func GetRegisteredRules() []analysis.Rule {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	return append([]analysis.Rule{}, registeredRules...)
+}
+
+// Main runs the application with the registered rules, the given rules and command line arguments
+// and exits with its exit code
+func Main(rules ...analysis.Rule) {
+	app := &App{Rules: append(GetRegisteredRules(), rules...), Stdin: os.Stdin, Stdout: os.Stdout, Stderr: os.Stderr}
+	os.Exit(app.Run(os.Args[1:]))
+}
+
+// Run runs the subcommand given by args and returns the exit code
+func (a *App) Run(args []string) int {
+	if len(args) == 0 {
+		a.printUsage(a.Stderr)
+		return ExitError
+	}
+
+	name := args[0]
+	if name == "help" || isHelpArg(name) {
+		a.printUsage(a.Stdout)
+		return ExitClean
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(a, args[1:])
+		}
+	}
+
+	fmt.Fprintf(a.Stderr, "goanalyzer: unknown command %q\n", name)
+	a.printUsage(a.Stderr)
+	return ExitError
+}
+
+func (a *App) printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: goanalyzer <command> [flags] [arguments]\n\nCommands:\n")
+	sorted := append([]*command{}, commands...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].name < sorted[j].name
+	})
+	for _, c := range sorted {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, strings.SplitN(c.summary, "\n", 2)[0])
+	}
+	fmt.Fprintf(w, "\nRun 'goanalyzer <command> -h' for help on the command.\n")
+}
+
+// getDir returns the absolute application directory, patterns and output paths are relative to it
+func (a *App) getDir() string {
+	dir := a.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// errorf prints the error and returns ExitError
+func (a *App) errorf(format string, args ...interface{}) int {
+	fmt.Fprintf(a.Stderr, "goanalyzer: "+format+"\n", args...)
+	return ExitError
+}
+
+// newFlagSet creates flag set of the command which prints errors and usage to stderr
+func (a *App) newFlagSet(name string) *flag.FlagSet {
+	var c *command
+	for _, cmd := range commands {
+		if cmd.name == name {
+			c = cmd
+		}
+	}
+
+	flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	flags.SetOutput(a.Stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.Stderr, "Usage: goanalyzer %s\n\n%s\n", c.usage, c.summary)
+		if hasFlags(flags) {
+			fmt.Fprintf(a.Stderr, "\nFlags:\n")
+			flags.PrintDefaults()
+		}
+	}
+	return flags
+}
+
+// parseFlags parses flags of the command, ok is false if the command should exit with the returned code
+func (a *App) parseFlags(flags *flag.FlagSet, args []string) (code int, ok bool) {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return ExitClean, false
+		}
+		return ExitError, false
+	}
+	return ExitClean, true
+}
+
+func isHelpArg(arg string) bool {
+	return arg == "-h" || arg == "-help" || arg == "--help"
+}
+
+func hasFlags(flags *flag.FlagSet) bool {
+	has := false
+	flags.VisitAll(func(*flag.Flag) {
+		has = true
+	})
+	return has
+}
+
+// stringList is the flag value which can be repeated or contain comma separated values
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
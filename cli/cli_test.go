@@ -0,0 +1,77 @@
+package cli_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/cli"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/internal/testrules"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestModule creates temporary module with the files and returns its directory
+func writeTestModule(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := map[string]string{"go.mod": "module example.com/m\n\ngo 1.15\n"}
+	for name, content := range files {
+		all[name] = content
+	}
+	for name, content := range all {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+// runApp runs the application in the directory and returns the exit code, stdout and stderr
+func runApp(dir string, args ...string) (int, string, string) {
+	return runAppWithInput(dir, "", args...)
+}
+
+// runAppWithInput runs the application in the directory with the given stdin
+func runAppWithInput(dir string, input string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	app := &cli.App{Rules: []analysis.Rule{testrules.PrintlnRule{}}, Dir: dir, Stdin: strings.NewReader(input), Stdout: &stdout, Stderr: &stderr}
+	code := app.Run(args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRegisterRules(t *testing.T) {
+	rules := cli.GetRegisteredRules()
+	cli.RegisterRules(testrules.PrintlnRule{})
+	assert.Equal(t, append(rules, testrules.PrintlnRule{}), cli.GetRegisteredRules())
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, stdout, _ := runApp("", "help")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Contains(t, stdout, "Usage: goanalyzer <command>")
+	assert.Contains(t, stdout, "  check ")
+
+	code, _, stderr := runApp("")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, "Usage: goanalyzer <command>")
+
+	code, _, stderr = runApp("", "lint")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, `goanalyzer: unknown command "lint"`)
+
+	code, _, stderr = runApp("", "check", "-h")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Contains(t, stderr, "Usage: goanalyzer check [flags] [packages]")
+	assert.Contains(t, stderr, "-fail-on")
+
+	code, _, stderr = runApp("", "check", "-unknown")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, "flag provided but not defined: -unknown")
+}
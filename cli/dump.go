@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+var dumpCommand = &command{
+	name:  "dump",
+	usage: "dump [flags] file.go[:line:col|:offset]",
+	summary: "Dump prints the syntax tree of the Go file.\n\nIf the position is given only the innermost node " +
+		"at the position and its ancestors are printed,\nelements of the node are printed up to -depth levels.\nLines and columns are 1-based, columns and offsets are in bytes.",
+	run: (*App).runDump,
+}
+
+// dumpFormats maps names of the dump formats to print formats
+var dumpFormats = map[string]syntax.PrintFormat{
+	"tree":    syntax.PrintFormatTree,
+	"compact": syntax.PrintFormatCompact,
+}
+
+func (a *App) runDump(args []string) int {
+	flags := a.newFlagSet("dump")
+	format := flags.String("format", "compact", "output format: compact or tree")
+	depth := flags.Int("depth", 0, "maximum depth of printed nodes, 0 means no limit or no elements of the node at the position")
+	if code, ok := a.parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return ExitError
+	}
+
+	printFormat, ok := dumpFormats[*format]
+	if !ok {
+		return a.errorf("unknown format %q, expected compact or tree", *format)
+	}
+	if *depth < 0 {
+		return a.errorf("invalid -depth value %d", *depth)
+	}
+
+	path, pos := parseFilePosition(flags.Arg(0))
+	src, err := ioutil.ReadFile(resolvePath(a.getDir(), path))
+	if err != nil {
+		return a.errorf("%v", err)
+	}
+
+	doc, _ := syntax.NewDocumentRegistry().AddDocument(path, string(src))
+	for _, d := range doc.Diagnostics {
+		span := doc.Text.GetLinePositionSpan(d.Location.Span, text.CharacterUnitByte)
+		fmt.Fprintf(a.Stderr, "%s:%d:%d: %s\n", path, span.Start.Line+1, span.Start.Character+1, d.GetMessage())
+	}
+
+	options := syntax.PrintOptions{Format: printFormat, MaxDepth: *depth, Document: doc}
+	if pos == "" {
+		syntax.PrintWithOptions(a.Stdout, doc.Root, options)
+		return ExitClean
+	}
+
+	offset, err := getOffset(doc.Text, pos)
+	if err != nil {
+		return a.errorf("%s: %v", path, err)
+	}
+	node := doc.FindNode(text.NewTextSpan(offset, 0))
+	if node == nil {
+		return a.errorf("%s: no node at %s", path, pos)
+	}
+	syntax.PrintPathTo(a.Stdout, node, options)
+	return ExitClean
+}
+
+// parseFilePosition splits file.go:line:col or file.go:offset argument into the path and the position
+func parseFilePosition(arg string) (path string, pos string) {
+	path = arg
+	for i := 0; i < 2 && !strings.HasSuffix(path, ".go"); i++ {
+		index := strings.LastIndex(path, ":")
+		if index < 0 {
+			break
+		}
+		path = path[:index]
+	}
+
+	if path == arg || !strings.HasSuffix(path, ".go") {
+		return arg, ""
+	}
+	return path, arg[len(path)+1:]
+}
+
+// getOffset converts line:col or offset position to the offset in the source text
+func getOffset(src *text.SourceText, pos string) (int, error) {
+	parts := strings.Split(pos, ":")
+	numbers := []int{}
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid position %q", pos)
+		}
+		numbers = append(numbers, n)
+	}
+
+	if len(numbers) == 1 {
+		if numbers[0] > src.Length() {
+			return 0, fmt.Errorf("offset %d is out of range", numbers[0])
+		}
+		return numbers[0], nil
+	}
+
+	line, col := numbers[0], numbers[1]
+	if line < 1 || line > src.LineCount() {
+		return 0, fmt.Errorf("line %d is out of range", line)
+	}
+	span := src.GetLineSpan(line - 1)
+	if col < 1 || col > span.Length()+1 {
+		return 0, fmt.Errorf("column %d is out of range", col)
+	}
+	return span.Start() + col - 1, nil
+}
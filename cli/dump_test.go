@@ -0,0 +1,99 @@
+package cli_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDump(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"a.go":   "package m\n\nfunc f() {\n\tprintln(1)\n}\n",
+		"b.go":   "package m\n\nvar x = \n",
+		"c.txt":  "text\n",
+		"d:e.go": "package m\n",
+	})
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runApp(dir, "dump", "-depth=1", "a.go")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "File 1:1-5:2\n"+
+		"  token package \"package\" 1:1-1:8\n"+
+		"  Ident 1:9-1:10 ...\n"+
+		"  FuncDecl 3:1-5:2 ...\n", stdout)
+
+	call := "File 1:1-5:2\n" +
+		"  FuncDecl 3:1-5:2\n" +
+		"    BlockStmt 3:10-5:2\n" +
+		"      ExprStmt 4:2-4:12\n" +
+		"        CallExpr 4:2-4:12\n" +
+		"          Ident 4:2-4:9 ...\n" +
+		"          token ( \"(\" 4:9-4:10\n" +
+		"          BasicLit 4:10-4:11 ...\n" +
+		"          token ) \")\" 4:11-4:12\n"
+	code, stdout, _ = runApp(dir, "dump", "-depth=1", "a.go:4:9")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, call, stdout)
+
+	code, stdout, _ = runApp(dir, "dump", "-depth=1", "a.go:30")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, call, stdout)
+
+	code, stdout, _ = runApp(dir, "dump", "a.go:4:10")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "File 1:1-5:2\n"+
+		"  FuncDecl 3:1-5:2\n"+
+		"    BlockStmt 3:10-5:2\n"+
+		"      ExprStmt 4:2-4:12\n"+
+		"        CallExpr 4:2-4:12\n"+
+		"          BasicLit 4:10-4:11 ...\n", stdout)
+
+	code, stdout, _ = runApp(dir, "dump", "-format=tree", "-depth=1", "a.go:4:10")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "node *ast.File\nparent <nil>\nelmnts: [\n"+
+		"\tnode *ast.FuncDecl\n\tparent *ast.File\n\telmnts: [\n"+
+		"\t\tnode *ast.BlockStmt\n\t\tparent *ast.FuncDecl\n\t\telmnts: [\n"+
+		"\t\t\tnode *ast.ExprStmt\n\t\t\tparent *ast.BlockStmt\n\t\t\telmnts: [\n"+
+		"\t\t\t\tnode *ast.CallExpr\n\t\t\t\tparent *ast.ExprStmt\n\t\t\t\telmnts: [\n"+
+		"\t\t\t\t\tnode *ast.BasicLit\n"+
+		"\t\t\t\t\tparent *ast.CallExpr\n"+
+		"\t\t\t\t\telmnts: [\n"+
+		"\t\t\t\t\t\ttoken 1 INT\n"+
+		"\t\t\t\t\t]\n"+
+		"\t\t\t\t]\n\t\t\t]\n\t\t]\n\t]\n]\n", stdout)
+
+	code, stdout, _ = runApp(dir, "dump", "d:e.go:1:1")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "File 1:1-1:10 ...\n", stdout)
+
+	code, stdout, stderr := runApp(dir, "dump", "-depth=1", "b.go")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "b.go:4:1: expected operand, found 'EOF'\n", stderr)
+	assert.Contains(t, stdout, "GenDecl 3:1-")
+}
+
+func TestDump_Errors(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{"a.go": "package m\n\nvar x = 1\n"})
+	defer os.RemoveAll(dir)
+
+	check := func(message string, args ...string) {
+		t.Helper()
+		code, stdout, stderr := runApp(dir, append([]string{"dump"}, args...)...)
+		assert.Equal(t, cli.ExitError, code)
+		assert.Equal(t, "", stdout)
+		assert.Contains(t, stderr, message)
+	}
+
+	check("Usage: goanalyzer dump [flags] file.go[:line:col|:offset]")
+	check("Usage: goanalyzer dump", "a.go", "b.go")
+	check(`unknown format "json"`, "-format=json", "a.go")
+	check("invalid -depth value -1", "-depth=-1", "a.go")
+	check("missing.go", "missing.go")
+	check("a.go: line 5 is out of range", "a.go:5:1")
+	check("a.go: column 11 is out of range", "a.go:3:11")
+	check("a.go: offset 100 is out of range", "a.go:100")
+	check("a.go: no node at 21", "a.go:21")
+	check(`a.go: invalid position "x:1"`, "a.go:x:1")
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// FindFiles returns sorted Go files of the patterns. Pattern is a .go file, a directory, a directory
+// followed by /... which matches the directory and its subdirectories, or a Go package pattern which is
+// resolved with go list. Files of local directories are not filtered by build constraints, so files for
+// all platforms are analyzed. Directories named testdata or vendor and directories starting with . or _
+// are skipped by /... patterns.
+func FindFiles(dir string, patterns []string, tests bool) ([]string, error) {
+	files := map[string]bool{}
+	goPatterns := []string{}
+	for _, pattern := range patterns {
+		path := pattern
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		if strings.HasSuffix(pattern, "/...") || pattern == "..." {
+			root := strings.TrimSuffix(strings.TrimSuffix(path, "..."), string(filepath.Separator))
+			if isLocalPattern(pattern) || isDir(root) {
+				if err := walkDir(dir, root, tests, files); err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+
+		info, err := os.Stat(path)
+		switch {
+		case err == nil && info.IsDir():
+			if err := addDirFiles(dir, path, tests, files); err != nil {
+				return nil, err
+			}
+		case err == nil:
+			if filepath.Ext(path) != ".go" {
+				return nil, fmt.Errorf("%s is not a Go file", pattern)
+			}
+			files[getDisplayPath(dir, path)] = true
+		case isLocalPattern(pattern) || filepath.Ext(pattern) == ".go":
+			return nil, err
+		default:
+			goPatterns = append(goPatterns, pattern)
+		}
+	}
+
+	if len(goPatterns) > 0 {
+		if err := addPackageFiles(dir, goPatterns, tests, files); err != nil {
+			return nil, err
+		}
+	}
+
+	result := []string{}
+	for file := range files {
+		result = append(result, file)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func isLocalPattern(pattern string) bool {
+	return filepath.IsAbs(pattern) || pattern == "." || pattern == ".." || pattern == "..." ||
+		strings.HasPrefix(pattern, "./") || strings.HasPrefix(pattern, "../")
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func walkDir(dir string, root string, tests bool, files map[string]bool) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+
+		name := info.Name()
+		if path != root && (name == "testdata" || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		return addDirFiles(dir, path, tests, files)
+	})
+}
+
+func addDirFiles(dir string, path string, tests bool, files map[string]bool) error {
+	infos, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || filepath.Ext(name) != ".go" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+			continue
+		}
+		if !tests && strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		files[getDisplayPath(dir, filepath.Join(path, name))] = true
+	}
+	return nil
+}
+
+func addPackageFiles(dir string, patterns []string, tests bool, files map[string]bool) error {
+	cfg := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedFiles,
+		Dir:   dir,
+		Tests: tests,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return err
+	}
+
+	errors := []string{}
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			errors = append(errors, e.Error())
+		}
+
+		// files of generated test main packages are in the build cache
+		if strings.HasSuffix(pkg.ID, ".test") {
+			continue
+		}
+		for _, file := range pkg.GoFiles {
+			files[getDisplayPath(dir, file)] = true
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("%s", strings.Join(errors, "\n"))
+	}
+	return nil
+}
+
+// getDisplayPath returns path relative to dir if the file is inside of it, otherwise the absolute path
+func getDisplayPath(dir string, path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if absDir, err := filepath.Abs(dir); err == nil {
+		if rel, err := filepath.Rel(absDir, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return abs
+}
@@ -0,0 +1,52 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindFiles(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"a.go":              "package m\n",
+		"a_test.go":         "package m\n",
+		"x/b.go":            "package x\n",
+		"x/testdata/c.go":   "package c\n",
+		"x/_skip/d.go":      "package d\n",
+		"vendor/v/e.go":     "package v\n",
+		"y/f_linux.go":      "package y\n",
+		"y/g.txt":           "text\n",
+		"y/z/h.go":          "package z\n",
+		".hidden/i.go":      "package i\n",
+		"y/z/testdata/j.go": "package j\n",
+	})
+	defer os.RemoveAll(dir)
+
+	check := func(expected []string, tests bool, patterns ...string) {
+		t.Helper()
+		files, err := cli.FindFiles(dir, patterns, tests)
+		if assert.NoError(t, err) {
+			for i := range expected {
+				expected[i] = filepath.FromSlash(expected[i])
+			}
+			assert.Equal(t, expected, files)
+		}
+	}
+
+	check([]string{"a.go", "a_test.go", "x/b.go", "y/f_linux.go", "y/z/h.go"}, true, "./...")
+	check([]string{"a.go", "x/b.go", "y/f_linux.go", "y/z/h.go"}, false, "./...")
+	check([]string{"y/f_linux.go", "y/z/h.go"}, true, "y/...")
+	check([]string{"a.go", "a_test.go"}, true, ".")
+	check([]string{"x/testdata/c.go"}, true, "x/testdata/c.go")
+	check([]string{"x/b.go"}, true, "example.com/m/x")
+	check([]string{"y/z/testdata/j.go"}, true, "./y/z/testdata")
+
+	_, err := cli.FindFiles(dir, []string{"y/g.txt"}, true)
+	assert.EqualError(t, err, "y/g.txt is not a Go file")
+
+	_, err = cli.FindFiles(dir, []string{"./missing"}, true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag/codefix"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+var fixCommand = &command{
+	name:  "fix",
+	usage: "fix [flags] [packages]",
+	summary: "Fix applies code fixes of the diagnostics to Go packages, directories or files.\n\n" +
+		"Files are written atomically and only if the fixed source parses without errors.\n" +
+		"Exit code is 1 if some fixes were not applied, e.g. because they conflict with other fixes.",
+	run: (*App).runFix,
+}
+
+// diffContext is the number of context lines in the diffs of fixes
+const diffContext = 3
+
+func (a *App) runFix(args []string) int {
+	flags := a.newFlagSet("fix")
+	var rules stringList
+	flags.Var(&rules, "rules", "comma separated ids or names of the rules whose fixes are applied, by default all rules")
+	dryRun := flags.Bool("dry-run", false, "print unified diff of the fixes instead of writing files")
+	interactive := flags.Bool("interactive", false, "show diff of each file and ask before writing it")
+	configPath := flags.String("config", "", "configuration file, by default .goanalyzer.json or .goanalyzer.yaml in the module root")
+	tests := flags.Bool("tests", true, "fix test files")
+	if code, ok := a.parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if *dryRun && *interactive {
+		return a.errorf("-dry-run and -interactive cannot be used together")
+	}
+
+	s, err := a.newSession(*configPath, flags.Args(), *tests)
+	if err != nil {
+		return a.errorf("%v", err)
+	}
+
+	ids, err := s.getRuleIDs(rules)
+	if err != nil {
+		return a.errorf("%v", err)
+	}
+
+	code := ExitClean
+	applied, files := 0, 0
+	input := bufio.NewReader(a.getStdin())
+	all := false
+	for _, r := range codefix.ApplyAll(s.registry, s.analyze(), codefix.RuleFilter(ids...)) {
+		for _, rejection := range r.Rejected {
+			a.printRejection(r, rejection)
+			code = getWorseCode(code, ExitFindings)
+		}
+		if !r.IsChanged() {
+			continue
+		}
+
+		// fixed source always parses, fixes which break the syntax are rejected
+		diff := text.GetUnifiedDiff(r.Document.Path, r.Document.Path, r.Document.Text, r.Fixed, diffContext)
+		if *dryRun {
+			fmt.Fprint(a.Stdout, diff)
+			continue
+		}
+
+		if *interactive && !all {
+			fmt.Fprint(a.Stdout, diff)
+			answer := a.ask(input, fmt.Sprintf("Apply %s to %s? [y,n,a,q] ", formatFixCount(len(r.Applied)), r.Document.Path))
+			if answer == "q" {
+				break
+			}
+			if answer == "a" {
+				all = true
+			} else if answer != "y" {
+				continue
+			}
+		}
+
+		if err := writeFixedFile(resolvePath(s.dir, r.Document.Path), r); err != nil {
+			fmt.Fprintf(a.Stderr, "goanalyzer: %s: %v\n", r.Document.Path, err)
+			code = ExitError
+			continue
+		}
+		fmt.Fprintf(a.Stdout, "%s: applied %s\n", r.Document.Path, formatFixCount(len(r.Applied)))
+		applied += len(r.Applied)
+		files++
+	}
+
+	if !*dryRun {
+		if applied == 0 {
+			fmt.Fprintf(a.Stdout, "no fixes applied\n")
+		} else {
+			fmt.Fprintf(a.Stdout, "applied %s in %s\n", formatFixCount(applied), formatCount(files, "file", "files"))
+		}
+	}
+	return code
+}
+
+// getRuleIDs returns ids of the rules given by ids or names
+func (s *session) getRuleIDs(rules []string) ([]string, error) {
+	ids := []string{}
+	for _, rule := range rules {
+		found := false
+		for _, info := range s.getRules() {
+			if rule == info.ID || rule == info.Name {
+				ids = append(ids, info.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown rule %q", rule)
+		}
+	}
+	return ids, nil
+}
+
+func (a *App) printRejection(r *codefix.Result, rejection codefix.Rejection) {
+	d := rejection.Diagnostic
+	pos := r.Document.Text.GetLinePosition(d.Location.Span.Start(), text.CharacterUnitByte)
+	fmt.Fprintf(a.Stderr, "goanalyzer: %s:%d:%d: fix of %s not applied: %s\n",
+		r.Document.Path, pos.Line+1, pos.Character+1, d.ID, rejection.Reason)
+}
+
+// ask prints the question and returns lower case answer, "q" is returned at the end of input
+func (a *App) ask(input *bufio.Reader, question string) string {
+	fmt.Fprint(a.Stdout, question)
+	line, err := input.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(a.Stdout)
+		return "q"
+	}
+	return strings.ToLower(strings.TrimSpace(line))
+}
+
+func (a *App) getStdin() io.Reader {
+	if a.Stdin != nil {
+		return a.Stdin
+	}
+	return strings.NewReader("")
+}
+
+func formatFixCount(count int) string {
+	return formatCount(count, "fix", "fixes")
+}
+
+func formatCount(count int, singular string, plural string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// getWorseCode returns the exit code which reports more severe problem
+func getWorseCode(code1 int, code2 int) int {
+	if code2 > code1 {
+		return code2
+	}
+	return code1
+}
+
+// writeFixedFile writes the fixed source if the file was not changed since it was read
+func writeFixedFile(path string, r *codefix.Result) error {
+	current, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if string(current) != r.Document.Text.String() {
+		return fmt.Errorf("file was changed after it was analyzed, file is not written")
+	}
+	return writeFileAtomic(path, r.Fixed.String())
+}
+
+// writeFileAtomic writes the content to a temporary file in the directory of path and renames it to path,
+// so path has either the old or the new content. File permissions are kept.
+func writeFileAtomic(path string, content string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.WriteString(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), info.Mode().Perm())
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
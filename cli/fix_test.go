@@ -0,0 +1,119 @@
+package cli_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+var fixFiles = map[string]string{
+	"a.go": "package m\n\nfunc f() {\n\tprintln()\n\tprintln(1)\n}\n",
+	"b.go": "package m\n\nfunc g() {\n\tprintln()\n}\n",
+	"c.go": "package m\n\nfunc h() {\n\tprint()\n}\n",
+}
+
+func readTestFile(t *testing.T, dir string, name string) string {
+	content, err := ioutil.ReadFile(filepath.Join(dir, name))
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestFix(t *testing.T) {
+	dir := writeTestModule(t, fixFiles)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.Chmod(filepath.Join(dir, "b.go"), 0600))
+
+	code, stdout, stderr := runApp(dir, "fix", "-rules=no-println")
+	assert.Equal(t, cli.ExitClean, code, stderr)
+	assert.Equal(t, "a.go: applied 2 fixes\nb.go: applied 1 fix\napplied 3 fixes in 2 files\n", stdout)
+	assert.Equal(t, "package m\n\nfunc f() {\n\tprint()\n\tprint(1)\n}\n", readTestFile(t, dir, "a.go"))
+	assert.Equal(t, "package m\n\nfunc g() {\n\tprint()\n}\n", readTestFile(t, dir, "b.go"))
+
+	info, err := os.Stat(filepath.Join(dir, "b.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 4)
+
+	code, stdout, _ = runApp(dir, "fix")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "no fixes applied\n", stdout)
+}
+
+func TestFix_DryRun(t *testing.T) {
+	dir := writeTestModule(t, fixFiles)
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runApp(dir, "fix", "--dry-run", "a.go", "c.go")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, ""+
+		"--- a.go\n"+
+		"+++ a.go\n"+
+		"@@ -1,6 +1,6 @@\n"+
+		" package m\n"+
+		" \n"+
+		" func f() {\n"+
+		"-\tprintln()\n"+
+		"+\tprint()\n"+
+		"-\tprintln(1)\n"+
+		"+\tprint(1)\n"+
+		" }\n", stdout)
+	assert.Equal(t, fixFiles["a.go"], readTestFile(t, dir, "a.go"))
+
+	code, stdout, _ = runApp(dir, "fix", "-dry-run", "-rules=GA0003")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "", stdout)
+}
+
+func TestFix_Interactive(t *testing.T) {
+	dir := writeTestModule(t, fixFiles)
+	defer os.RemoveAll(dir)
+
+	code, stdout, _ := runAppWithInput(dir, "n\n", "fix", "-interactive")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Contains(t, stdout, "+\tprint(1)\n }\nApply 2 fixes to a.go? [y,n,a,q] ")
+	assert.Contains(t, stdout, "Apply 1 fix to b.go? [y,n,a,q] \nno fixes applied\n")
+	assert.Equal(t, fixFiles["a.go"], readTestFile(t, dir, "a.go"))
+
+	code, stdout, _ = runAppWithInput(dir, "q\n", "fix", "-interactive")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.NotContains(t, stdout, "b.go")
+
+	code, stdout, _ = runAppWithInput(dir, "N\nY\n", "fix", "-interactive")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Contains(t, stdout, "b.go: applied 1 fix\napplied 1 fix in 1 file\n")
+	assert.Equal(t, fixFiles["a.go"], readTestFile(t, dir, "a.go"))
+	assert.Equal(t, "package m\n\nfunc g() {\n\tprint()\n}\n", readTestFile(t, dir, "b.go"))
+
+	code, _, stderr := runApp(dir, "fix", "-interactive", "-dry-run")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, "-dry-run and -interactive cannot be used together")
+}
+
+func TestFix_NotApplied(t *testing.T) {
+	dir := writeTestModule(t, map[string]string{
+		"a.go": "package m\n\nfunc f() {\n\tprintln()\n}\n\nvar x = \n",
+		"b.go": "package m\n\nfunc g() {\n\tprintln()\n}\n",
+	})
+	defer os.RemoveAll(dir)
+
+	code, stdout, stderr := runApp(dir, "fix")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Equal(t, "b.go: applied 1 fix\napplied 1 fix in 1 file\n", stdout)
+	assert.Contains(t, stderr, "goanalyzer: a.go:4:2: fix of GA9000 not applied: source has syntax errors\n")
+	assert.Contains(t, readTestFile(t, dir, "a.go"), "println()")
+
+	code, stdout, _ = runApp(dir, "fix", "-dry-run")
+	assert.Equal(t, cli.ExitFindings, code)
+	assert.Equal(t, "", stdout)
+
+	code, _, stderr = runApp(dir, "fix", "-rules=GA9000,unknown")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, `goanalyzer: unknown rule "unknown"`)
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/query"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+var queryCommand = &command{
+	name:  "query",
+	usage: "query [flags] <query> [packages]",
+	summary: "Query prints syntax nodes matching the structural search query.\n\n" +
+		"The query is a sequence of node kinds or named child slots separated by > for children\n" +
+		"or by spaces for descendants, with optional attribute filters, e.g.\n\n" +
+		"\tFuncDecl > Body ReturnStmt[Results.count>2]\n" +
+		"\tCallExpr[Fun.Sel.Name~=\"^Print\"], CallExpr[Fun.Name=println]",
+	run: (*App).runQuery,
+}
+
+// maxMatchText is the maximum length of the matched node text printed after the location
+const maxMatchText = 80
+
+func (a *App) runQuery(args []string) int {
+	flags := a.newFlagSet("query")
+	count := flags.Bool("count", false, "print only the number of matches")
+	tests := flags.Bool("tests", true, "search test files")
+	if code, ok := a.parseFlags(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return ExitError
+	}
+
+	q, err := query.Compile(flags.Arg(0))
+	if err != nil {
+		return a.errorf("invalid query: %v", err)
+	}
+
+	s, err := a.newSession("", flags.Args()[1:], *tests)
+	if err != nil {
+		return a.errorf("%v", err)
+	}
+
+	matches := 0
+	for _, doc := range s.docs {
+		for _, node := range q.Find(doc) {
+			matches++
+			if !*count {
+				fmt.Fprintln(a.Stdout, formatMatch(doc, node))
+			}
+		}
+	}
+
+	if *count {
+		fmt.Fprintln(a.Stdout, matches)
+	}
+	return ExitClean
+}
+
+// formatMatch returns location, kind and the first line of the node text
+func formatMatch(doc *syntax.Document, node syntax.Node) string {
+	span := doc.GetSpan(node)
+	pos := doc.Text.GetLinePosition(span.Start(), text.CharacterUnitByte)
+
+	str := doc.Text.GetSubText(span)
+	if i := strings.IndexAny(str, "\r\n"); i >= 0 {
+		str = str[:i] + " ..."
+	}
+	if runes := []rune(str); len(runes) > maxMatchText {
+		str = string(runes[:maxMatchText]) + " ..."
+	}
+
+	kind := syntaxkind.GetAstKind(node.GetAstNode())
+	return fmt.Sprintf("%s:%d:%d: %v: %s", doc.Path, pos.Line+1, pos.Character+1, kind, str)
+}
@@ -0,0 +1,51 @@
+package cli_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuery(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	dir := writeTestModule(t, map[string]string{
+		"a.go":        "package m\n\nfunc f() (int, int, int) {\n\tif true {\n\t\treturn 1, 2, 3\n\t}\n\treturn 4, 5, 6\n}\n",
+		"b/b.go":      "package b\n\nfunc g() {\n\tprintln(\"" + long + "\")\n\tfunc() {\n\t}()\n}\n",
+		"b/b_test.go": "package b\n\nfunc h() (int, int, int) {\n\treturn 1, 2, 3\n}\n",
+	})
+	defer os.RemoveAll(dir)
+
+	code, stdout, stderr := runApp(dir, "query", "FuncDecl > Body ReturnStmt[Results.count>2]")
+	assert.Equal(t, cli.ExitClean, code, stderr)
+	assert.Equal(t, ""+
+		"a.go:5:3: ReturnStmt: return 1, 2, 3\n"+
+		"a.go:7:2: ReturnStmt: return 4, 5, 6\n"+
+		"b/b_test.go:4:2: ReturnStmt: return 1, 2, 3\n", stdout)
+
+	code, stdout, _ = runApp(dir, "query", "-tests=false", "-count", "ReturnStmt", "./...")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "2\n", stdout)
+
+	code, stdout, _ = runApp(dir, "query", "CallExpr[Fun.Name=println], ExprStmt > CallExpr[Fun.Type]", "./b")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, ""+
+		"b/b.go:4:2: CallExpr: println(\""+strings.Repeat("x", 71)+" ...\n"+
+		"b/b.go:5:2: CallExpr: func() { ...\n", stdout)
+
+	code, stdout, _ = runApp(dir, "query", "GoStmt")
+	assert.Equal(t, cli.ExitClean, code)
+	assert.Equal(t, "", stdout)
+}
+
+func TestQuery_Errors(t *testing.T) {
+	code, _, stderr := runApp("", "query")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, "Usage: goanalyzer query [flags] <query> [packages]")
+
+	code, _, stderr = runApp("", "query", "FuncDecl >")
+	assert.Equal(t, cli.ExitError, code)
+	assert.Contains(t, stderr, "goanalyzer: invalid query: column 11: expected kind, slot or *, found end of query")
+}
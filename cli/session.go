@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/config"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+)
+
+// syntaxErrorInfo describes syntax errors which are reported for all documents
+var syntaxErrorInfo = analysis.RuleInfo{
+	ID:              syntax.SyntaxErrorID,
+	Name:            "syntax-error",
+	Title:           "Syntax error",
+	DefaultSeverity: diag.SeverityError,
+}
+
+// session holds configuration, documents and rules of the command run
+type session struct {
+	dir      string
+	config   *config.Config
+	registry *syntax.DocumentRegistry
+	docs     []*syntax.Document
+	driver   *analysis.Driver
+}
+
+// newSession loads configuration and documents of the patterns. If configPath is empty
+// configuration is loaded from the module root of the application directory.
+func (a *App) newSession(configPath string, patterns []string, tests bool) (*session, error) {
+	dir := a.getDir()
+	s := &session{dir: dir, registry: syntax.NewDocumentRegistry()}
+
+	var err error
+	if configPath != "" {
+		s.config, err = config.LoadFile(resolvePath(dir, configPath))
+	} else {
+		s.config, err = config.LoadFromModuleRoot(dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.config.Dir = dir
+
+	s.driver = analysis.NewDriver(a.Rules...)
+	s.driver.Settings = s.config
+	if err := s.config.Validate(s.getRules()); err != nil {
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	files, err := FindFiles(dir, patterns, tests)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(resolvePath(dir, file))
+		if err != nil {
+			return nil, err
+		}
+		// syntax errors are kept in document diagnostics
+		doc, _ := s.registry.AddDocument(file, string(src))
+		s.docs = append(s.docs, doc)
+	}
+	return s, nil
+}
+
+// getRules returns metadata of the rules and built-in diagnostics
+func (s *session) getRules() []analysis.RuleInfo {
+	return append([]analysis.RuleInfo{syntaxErrorInfo, analysis.RuleFailureInfo, analysis.SuppressionInfo}, s.driver.GetRules()...)
+}
+
+func (s *session) getDescriptors() []diag.Descriptor {
+	return s.getRules()
+}
+
+// analyze runs the rules and returns sorted diagnostics with syntax errors
+func (s *session) analyze() []diag.Diagnostic {
+	diags := s.driver.AnalyzeDocuments(s.docs)
+	for _, doc := range s.docs {
+		diags = append(diags, doc.Diagnostics...)
+	}
+	diag.SortDiagnostics(diags)
+	return diags
+}
+
+// resolvePath returns path relative to dir
+func resolvePath(dir string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
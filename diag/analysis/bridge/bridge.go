@@ -0,0 +1,164 @@
+package bridge
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	goanalysis "golang.org/x/tools/go/analysis"
+)
+
+// SyntaxAnalyzer builds syntax trees of the package files, its result is []*syntax.Document.
+// Analyzers created by NewAnalyzer require it, so trees are built once per package.
+var SyntaxAnalyzer = &goanalysis.Analyzer{
+	Name:       "syntaxtree",
+	Doc:        "builds goanalyzer syntax trees of the package files",
+	Run:        runSyntaxAnalyzer,
+	ResultType: reflect.TypeOf([]*syntax.Document{}),
+}
+
+// runSyntaxAnalyzer builds documents of the package files. Files which can not be read as the driver parsed them
+// are reported and skipped, so other files are still analyzed.
+func runSyntaxAnalyzer(pass *goanalysis.Pass) (interface{}, error) {
+	docs := []*syntax.Document{}
+	for _, file := range pass.Files {
+		tokFile := pass.Fset.File(file.Pos())
+		if tokFile == nil {
+			continue
+		}
+
+		src, err := pass.ReadFile(tokFile.Name())
+		if err != nil {
+			pass.Reportf(file.Pos(), "cannot read file, it is not analyzed: %v", err)
+			continue
+		}
+		if len(src) != tokFile.Size() {
+			pass.Reportf(file.Pos(), "file was changed after it was parsed, it is not analyzed")
+			continue
+		}
+		docs = append(docs, syntax.NewDocument(pass.Fset, tokFile.Name(), string(src), file))
+	}
+	return docs, nil
+}
+
+// NewAnalyzer wraps the rule as analyzer. Diagnostics of the rule are reported with the rule id as category,
+// code fixes are reported as suggested fixes and additional locations as related information.
+// Suppressed diagnostics and diagnostics outside of the files of the pass are not reported.
+func NewAnalyzer(rule analysis.Rule) *goanalysis.Analyzer {
+	info := rule.Metadata()
+	return &goanalysis.Analyzer{
+		Name:     GetAnalyzerName(info),
+		Doc:      getAnalyzerDoc(info),
+		Requires: []*goanalysis.Analyzer{SyntaxAnalyzer},
+		Run: func(pass *goanalysis.Pass) (interface{}, error) {
+			docs := pass.ResultOf[SyntaxAnalyzer].([]*syntax.Document)
+			paths := map[string]*syntax.Document{}
+			for _, doc := range docs {
+				paths[doc.Path] = doc
+			}
+
+			// passes of different packages run concurrently, so each of them has its own driver
+			driver := analysis.NewDriver(rule)
+			for _, d := range driver.AnalyzeDocuments(docs) {
+				if d.IsSuppressed() {
+					continue
+				}
+				if result, ok := getDiagnostic(paths, d); ok {
+					pass.Report(result)
+				}
+			}
+			return nil, nil
+		},
+	}
+}
+
+// NewAnalyzers wraps each rule as analyzer
+func NewAnalyzers(rules ...analysis.Rule) []*goanalysis.Analyzer {
+	analyzers := []*goanalysis.Analyzer{}
+	for _, rule := range rules {
+		analyzers = append(analyzers, NewAnalyzer(rule))
+	}
+	return analyzers
+}
+
+// GetAnalyzerName returns name of the analyzer of the rule. Analyzer names must be identifiers,
+// so other characters of the rule name are replaced with underscores. Rules without name use lowercase id.
+func GetAnalyzerName(info analysis.RuleInfo) string {
+	name := info.Name
+	if name == "" {
+		name = strings.ToLower(info.ID)
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, name)
+
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "_" + name
+	}
+	return name
+}
+
+func getAnalyzerDoc(info analysis.RuleInfo) string {
+	title := info.Title
+	if title == "" {
+		title = fmt.Sprintf("reports %s diagnostics", info.ID)
+	}
+
+	doc := fmt.Sprintf("%s: %s", info.ID, title)
+	if info.Help != "" {
+		doc += "\n\n" + info.Help
+	}
+	if info.HelpURI != "" {
+		doc += "\n\nSee " + info.HelpURI
+	}
+	return doc
+}
+
+// getDiagnostic converts the diagnostic, ok is false if the diagnostic is not in the files of the pass
+func getDiagnostic(paths map[string]*syntax.Document, d diag.Diagnostic) (goanalysis.Diagnostic, bool) {
+	doc := paths[d.Location.Path]
+	if doc == nil {
+		return goanalysis.Diagnostic{}, false
+	}
+
+	pos, end := getPositions(doc, d.Location)
+	result := goanalysis.Diagnostic{
+		Pos:      pos,
+		End:      end,
+		Category: d.ID,
+		Message:  d.GetMessage(),
+	}
+
+	for _, loc := range d.AdditionalLocations {
+		if related := paths[loc.Path]; related != nil {
+			pos, end := getPositions(related, loc)
+			result.Related = append(result.Related, goanalysis.RelatedInformation{Pos: pos, End: end, Message: loc.Message})
+		}
+	}
+
+	for _, fix := range d.Fixes {
+		suggested := goanalysis.SuggestedFix{Message: fix.Title}
+		for _, c := range fix.Changes {
+			suggested.TextEdits = append(suggested.TextEdits, goanalysis.TextEdit{
+				Pos:     doc.GetPos(c.Span.Start()),
+				End:     doc.GetPos(c.Span.End()),
+				NewText: []byte(c.NewText),
+			})
+		}
+		result.SuggestedFixes = append(result.SuggestedFixes, suggested)
+	}
+	return result, true
+}
+
+func getPositions(doc *syntax.Document, loc diag.Location) (token.Pos, token.Pos) {
+	return doc.GetPos(loc.Span.Start()), doc.GetPos(loc.Span.End())
+}
@@ -0,0 +1,107 @@
+package bridge_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/analysis/bridge"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/internal/testrules"
+	"github.com/stretchr/testify/assert"
+	goanalysis "golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestNewAnalyzer(t *testing.T) {
+	analyzer := bridge.NewAnalyzer(testrules.PrintlnRule{})
+	assert.Equal(t, "no_println", analyzer.Name)
+	assert.Equal(t, "GA9000: Avoid println", analyzer.Doc)
+	assert.NoError(t, goanalysis.Validate([]*goanalysis.Analyzer{analyzer}))
+
+	results := analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, "a")
+	if assert.Len(t, results, 1) {
+		diags := results[0].Diagnostics
+		if assert.Len(t, diags, 2) {
+			assert.Equal(t, "GA9000", diags[0].Category)
+			assert.Equal(t, "Use print", diags[0].SuggestedFixes[0].Message)
+			if assert.Len(t, diags[0].Related, 1) {
+				assert.Equal(t, "call", diags[0].Related[0].Message)
+				assert.Equal(t, diags[0].Pos, diags[0].Related[0].Pos)
+			}
+		}
+	}
+}
+
+// otherFileRule reports diagnostics in the file which is not analyzed
+type otherFileRule struct{}
+
+func (r otherFileRule) Metadata() analysis.RuleInfo {
+	return analysis.RuleInfo{ID: "GA9001", Name: "other-file", DefaultSeverity: diag.SeverityWarning}
+}
+
+func (r otherFileRule) Initialize(ctx *analysis.RuleContext) {
+	ctx.RegisterFileEndAction(func(ctx *analysis.FileContext) {
+		d := ctx.NewDiagnostic(ctx.Document.Root, "other file")
+		d.Location.Path = "other.go"
+		ctx.Report(d)
+	})
+}
+
+func TestNewAnalyzerSkipsDiagnosticsOutsideOfPass(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), bridge.NewAnalyzer(otherFileRule{}), "b")
+	if assert.Len(t, results, 1) {
+		assert.NoError(t, results[0].Err)
+		assert.Empty(t, results[0].Diagnostics)
+	}
+}
+
+func TestSyntaxAnalyzerSkipsChangedFiles(t *testing.T) {
+	fset := token.NewFileSet()
+	a, err := parser.ParseFile(fset, "a.go", "package a\n", parser.ParseComments)
+	assert.NoError(t, err)
+	b, err := parser.ParseFile(fset, "b.go", "package a\n\nvar x = 1\n", parser.ParseComments)
+	assert.NoError(t, err)
+
+	diags := []goanalysis.Diagnostic{}
+	pass := &goanalysis.Pass{
+		Fset:  fset,
+		Files: []*ast.File{a, b},
+		ReadFile: func(name string) ([]byte, error) {
+			if name == "a.go" {
+				return []byte("package a // changed\n"), nil
+			}
+			return []byte("package a\n\nvar x = 1\n"), nil
+		},
+		Report: func(d goanalysis.Diagnostic) {
+			diags = append(diags, d)
+		},
+	}
+
+	result, err := bridge.SyntaxAnalyzer.Run(pass)
+	assert.NoError(t, err)
+	docs := result.([]*syntax.Document)
+	if assert.Len(t, docs, 1) {
+		assert.Equal(t, "b.go", docs[0].Path)
+	}
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, a.Pos(), diags[0].Pos)
+		assert.Equal(t, "file was changed after it was parsed, it is not analyzed", diags[0].Message)
+	}
+}
+
+func TestNewAnalyzers(t *testing.T) {
+	analyzers := bridge.NewAnalyzers(testrules.PrintlnRule{})
+	assert.Len(t, analyzers, 1)
+	assert.Equal(t, []*goanalysis.Analyzer{bridge.SyntaxAnalyzer}, analyzers[0].Requires)
+}
+
+func TestGetAnalyzerName(t *testing.T) {
+	assert.Equal(t, "no_println", bridge.GetAnalyzerName(analysis.RuleInfo{ID: "GA1", Name: "no-println"}))
+	assert.Equal(t, "ga1000", bridge.GetAnalyzerName(analysis.RuleInfo{ID: "GA1000"}))
+	assert.Equal(t, "_1st", bridge.GetAnalyzerName(analysis.RuleInfo{ID: "GA1", Name: "1st"}))
+	assert.Equal(t, "_", bridge.GetAnalyzerName(analysis.RuleInfo{}))
+}
@@ -0,0 +1,10 @@
+package a
+
+func f() {
+	println("hello") // want "avoid println"
+	print("")
+	g := func() {
+		println() // want "avoid println"
+	}
+	g()
+}
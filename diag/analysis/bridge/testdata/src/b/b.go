@@ -0,0 +1,5 @@
+package b
+
+func f() {
+	print("hello")
+}
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+)
+
+// FileContext is passed to actions of the rule while the file is analyzed
+type FileContext struct {
+	Document *syntax.Document
+	info     RuleInfo
+	settings RuleSettings
+	report   func(d diag.Diagnostic)
+}
+
+// NodeContext is passed to node actions
+type NodeContext struct {
+	*FileContext
+	Node syntax.Node
+}
+
+// TokenContext is passed to token actions
+type TokenContext struct {
+	*FileContext
+	Token syntax.Token
+}
+
+// GetRuleInfo returns metadata of the rule
+func (c *FileContext) GetRuleInfo() RuleInfo {
+	return c.info
+}
+
+// GetSeverity returns severity of the rule diagnostics in the document
+func (c *FileContext) GetSeverity() diag.Severity {
+	return c.settings.Severity
+}
+
+// GetOptions decodes options of the rule to v which is usually a pointer to struct with json tags.
+// Fields of v which are not in the options keep their values, so v can be initialized with defaults.
+func (c *FileContext) GetOptions(v interface{}) error {
+	if len(c.settings.Options) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(c.settings.Options)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid options of rule %s: %v", c.info.ID, err)
+	}
+	return nil
+}
+
+// NewDiagnostic creates diagnostic of the rule at the syntax element, it can be reported with Report
+func (c *FileContext) NewDiagnostic(elmt syntax.Element, message string, args ...interface{}) diag.Diagnostic {
+	location := diag.NewLocation(c.Document.Path, c.Document.GetSpan(elmt))
+	return diag.NewDiagnostic(c.info.ID, c.settings.Severity, location, message, args...)
+}
+
+// ReportAt reports diagnostic of the rule at the syntax element
+func (c *FileContext) ReportAt(elmt syntax.Element, message string, args ...interface{}) {
+	c.Report(c.NewDiagnostic(elmt, message, args...))
+}
+
+// Report reports the diagnostic, empty id is replaced with the rule id
+func (c *FileContext) Report(d diag.Diagnostic) {
+	if d.ID == "" {
+		d.ID = c.info.ID
+	}
+	c.report(d)
+}
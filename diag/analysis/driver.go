@@ -0,0 +1,200 @@
+package analysis
+
+import (
+	"fmt"
+	"go/token"
+	"runtime"
+	"sync"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+)
+
+// RuleFailureID is the id of diagnostics reported when an action of the rule panics
+const RuleFailureID = "GA0002"
+
+type nodeAction struct {
+	rule   int
+	action NodeAction
+}
+
+type tokenAction struct {
+	rule   int
+	action TokenAction
+}
+
+type fileAction struct {
+	rule   int
+	action FileAction
+}
+
+// Driver runs rules on documents. Each syntax tree is walked once and nodes and tokens are dispatched
+// to actions of all rules subscribed to their kinds in the order the actions were registered.
+type Driver struct {
+	rules          []RuleInfo
+	nodeActions    map[syntaxkind.AstKind][]nodeAction
+	tokenActions   map[token.Token][]tokenAction
+	fileEndActions []fileAction
+	// Settings returns settings of the rules for each document, default settings are used if it is nil
+	Settings SettingsProvider
+}
+
+// NewDriver creates driver and initializes the rules, it panics if rule ids are empty or not unique
+func NewDriver(rules ...Rule) *Driver {
+	d := &Driver{
+		nodeActions:  map[syntaxkind.AstKind][]nodeAction{},
+		tokenActions: map[token.Token][]tokenAction{},
+	}
+
+	ids := map[string]bool{}
+	for i, rule := range rules {
+		info := rule.Metadata()
+		if info.ID == "" {
+			panic("rule id is empty!")
+		}
+		if ids[info.ID] {
+			panic(fmt.Sprintf("rule %s is registered twice!", info.ID))
+		}
+		ids[info.ID] = true
+
+		d.rules = append(d.rules, info)
+		rule.Initialize(&RuleContext{rule: i, info: info, driver: d})
+	}
+	return d
+}
+
+// GetRules returns metadata of the driver rules
+func (d *Driver) GetRules() []RuleInfo {
+	return append([]RuleInfo{}, d.rules...)
+}
+
+// GetDescriptors returns descriptors of the driver rules
+func (d *Driver) GetDescriptors() []diag.Descriptor {
+	return append([]diag.Descriptor{}, d.rules...)
+}
+
+// Analyze runs the rules on the document and returns sorted diagnostics. Diagnostics suppressed by directives
+// are returned with suppressions, malformed and unused directives are reported. Syntax errors of the document are not included.
+func (d *Driver) Analyze(doc *syntax.Document) []diag.Diagnostic {
+	diags := []diag.Diagnostic{}
+	report := func(diagnostic diag.Diagnostic) {
+		diags = append(diags, diagnostic)
+	}
+
+	// contexts of disabled rules are nil
+	files := make([]*FileContext, len(d.rules))
+	for i, info := range d.rules {
+		settings := d.getRuleSettings(doc, info)
+		if !settings.Disabled {
+			files[i] = &FileContext{Document: doc, info: info, settings: settings, report: report}
+		}
+	}
+
+	if doc.Root != nil {
+		d.walk(files, doc.Root)
+	}
+
+	for _, a := range d.fileEndActions {
+		ctx := files[a.rule]
+		if ctx == nil {
+			continue
+		}
+		d.run(ctx, doc.Root, func() { a.action(ctx) })
+	}
+
+	diags = append(diags, d.applySuppressions(doc, diags, files)...)
+	diag.SortDiagnostics(diags)
+	return diags
+}
+
+// applySuppressions marks diagnostics suppressed by directives and returns diagnostics of malformed and unused directives
+func (d *Driver) applySuppressions(doc *syntax.Document, diags []diag.Diagnostic, files []*FileContext) []diag.Diagnostic {
+	rules := append([]RuleInfo{RuleFailureInfo}, d.rules...)
+	enabled := []bool{true}
+	for _, ctx := range files {
+		enabled = append(enabled, ctx != nil)
+	}
+
+	settings := d.getRuleSettings(doc, SuppressionInfo)
+	result := []diag.Diagnostic{}
+	applySuppressions(doc, diags, rules, enabled, func(diagnostic diag.Diagnostic) {
+		if !settings.Disabled {
+			diagnostic.Severity = settings.Severity
+			result = append(result, diagnostic)
+		}
+	})
+	return result
+}
+
+// AnalyzeDocuments runs the rules on the documents concurrently and returns sorted diagnostics
+func (d *Driver) AnalyzeDocuments(docs []*syntax.Document) []diag.Diagnostic {
+	bag := diag.NewBag()
+	jobs := make(chan *syntax.Document)
+	wg := sync.WaitGroup{}
+	for i := 0; i < runtime.GOMAXPROCS(0); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for doc := range jobs {
+				bag.AddRange(d.Analyze(doc))
+			}
+		}()
+	}
+
+	for _, doc := range docs {
+		jobs <- doc
+	}
+	close(jobs)
+	wg.Wait()
+	return bag.GetDiagnostics()
+}
+
+func (d *Driver) getRuleSettings(doc *syntax.Document, info RuleInfo) RuleSettings {
+	if d.Settings == nil {
+		return GetDefaultSettings(info)
+	}
+	return d.Settings.GetRuleSettings(doc, info)
+}
+
+func (d *Driver) walk(files []*FileContext, node syntax.Node) {
+	for _, a := range d.nodeActions[syntaxkind.GetAstKind(node.GetAstNode())] {
+		if files[a.rule] == nil {
+			continue
+		}
+		ctx := &NodeContext{FileContext: files[a.rule], Node: node}
+		d.run(ctx.FileContext, node, func() { a.action(ctx) })
+	}
+
+	for _, elmt := range node.GetElements() {
+		switch e := elmt.(type) {
+		case syntax.Node:
+			d.walk(files, e)
+		case syntax.Token:
+			for _, a := range d.tokenActions[e.GetKind()] {
+				if files[a.rule] == nil {
+					continue
+				}
+				ctx := &TokenContext{FileContext: files[a.rule], Token: e}
+				d.run(ctx.FileContext, e, func() { a.action(ctx) })
+			}
+		}
+	}
+}
+
+// run calls the action and reports panic of the action as a diagnostic at the element,
+// so a failing rule does not stop other rules
+func (d *Driver) run(ctx *FileContext, elmt syntax.Element, action func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			var loc diag.Location
+			if elmt != nil {
+				loc = diag.NewLocation(ctx.Document.Path, ctx.Document.GetSpan(elmt))
+			} else {
+				loc = diag.Location{Path: ctx.Document.Path}
+			}
+			ctx.report(diag.NewDiagnostic(RuleFailureID, diag.SeverityError, loc, "rule %s failed: %v", ctx.info.ID, r))
+		}
+	}()
+	action()
+}
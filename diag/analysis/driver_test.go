@@ -0,0 +1,233 @@
+package analysis_test
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+	"github.com/a6cexz/goanalyzer/internal/testrules"
+	"github.com/stretchr/testify/assert"
+)
+
+// emptyStringRule reports empty string literals
+type emptyStringRule struct{}
+
+func (r emptyStringRule) Metadata() analysis.RuleInfo {
+	return analysis.RuleInfo{ID: "GA9001", Name: "empty-string", DefaultSeverity: diag.SeverityInfo}
+}
+
+func (r emptyStringRule) Initialize(ctx *analysis.RuleContext) {
+	ctx.RegisterTokenAction(func(ctx *analysis.TokenContext) {
+		if ctx.Token.GetText() == `""` {
+			ctx.ReportAt(ctx.Token, "empty string literal")
+		}
+	}, token.STRING)
+}
+
+// recordingRule records visited node kinds, tokens and the file end
+type recordingRule struct {
+	id     string
+	events []string
+}
+
+func (r *recordingRule) Metadata() analysis.RuleInfo {
+	return analysis.RuleInfo{ID: r.id, DefaultSeverity: diag.SeverityWarning}
+}
+
+func (r *recordingRule) Initialize(ctx *analysis.RuleContext) {
+	ctx.RegisterNodeAction(func(ctx *analysis.NodeContext) {
+		r.events = append(r.events, fmt.Sprintf("%s:%T", ctx.GetRuleInfo().ID, ctx.Node.GetAstNode()))
+	}, syntaxkind.AstFuncDecl, syntaxkind.AstCallExpr)
+	ctx.RegisterTokenAction(func(ctx *analysis.TokenContext) {
+		r.events = append(r.events, fmt.Sprintf("%s:%s", ctx.GetRuleInfo().ID, ctx.Token.GetText()))
+	}, token.RETURN)
+	ctx.RegisterFileEndAction(func(ctx *analysis.FileContext) {
+		r.events = append(r.events, fmt.Sprintf("%s:end %s", ctx.GetRuleInfo().ID, ctx.Document.Path))
+	})
+}
+
+// panicRule panics on each identifier
+type panicRule struct{}
+
+func (r panicRule) Metadata() analysis.RuleInfo {
+	return analysis.RuleInfo{ID: "GA9002", DefaultSeverity: diag.SeverityWarning}
+}
+
+func (r panicRule) Initialize(ctx *analysis.RuleContext) {
+	ctx.RegisterNodeAction(func(ctx *analysis.NodeContext) {
+		panic("boom")
+	}, syntaxkind.AstIdent)
+}
+
+func addTestDocument(t *testing.T, registry *syntax.DocumentRegistry, path string, src string) *syntax.Document {
+	doc, err := registry.AddDocument(path, src)
+	assert.Nil(t, err)
+	return doc
+}
+
+func TestDriver_Rules(t *testing.T) {
+	asttest.RunAnalyzer(t, analysis.NewDriver(testrules.PrintlnRule{}, emptyStringRule{}), "testdata/rules")
+}
+
+func TestDriver_Dispatch(t *testing.T) {
+	src := `package a
+
+func f() int {
+	g(h())
+	return 1
+}
+`
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", src)
+	r1 := &recordingRule{id: "R1"}
+	r2 := &recordingRule{id: "R2"}
+	diags := analysis.NewDriver(r1, r2).Analyze(doc)
+	assert.Empty(t, diags)
+
+	assert.Equal(t, []string{
+		"R1:*ast.FuncDecl",
+		"R1:*ast.CallExpr",
+		"R1:*ast.CallExpr",
+		"R1:return",
+		"R1:end a.go",
+	}, r1.events)
+	assert.Equal(t, []string{
+		"R2:*ast.FuncDecl",
+		"R2:*ast.CallExpr",
+		"R2:*ast.CallExpr",
+		"R2:return",
+		"R2:end a.go",
+	}, r2.events)
+}
+
+func TestDriver_RulePanic(t *testing.T) {
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", "package a\n\nfunc f() { println(\"\") }\n")
+	diags := analysis.NewDriver(panicRule{}, emptyStringRule{}).Analyze(doc)
+
+	messages := []string{}
+	for _, d := range diags {
+		messages = append(messages, d.String())
+	}
+	assert.Equal(t, []string{
+		"a.go[8..9): error GA0002: rule GA9002 failed: boom",
+		"a.go[16..17): error GA0002: rule GA9002 failed: boom",
+		"a.go[22..29): error GA0002: rule GA9002 failed: boom",
+		"a.go[30..32): info GA9001: empty string literal",
+	}, messages)
+}
+
+func TestDriver_AnalyzeDocuments(t *testing.T) {
+	registry := syntax.NewDocumentRegistry()
+	docs := []*syntax.Document{}
+	for i := 0; i < 20; i++ {
+		src := fmt.Sprintf("package a\n\nfunc f%d() { println(\"\") }\n", i)
+		docs = append(docs, addTestDocument(t, registry, fmt.Sprintf("a%02d.go", i), src))
+	}
+
+	diags := analysis.NewDriver(testrules.PrintlnRule{}, emptyStringRule{}).AnalyzeDocuments(docs)
+	assert.Equal(t, 40, len(diags))
+	assert.Equal(t, "a00.go", diags[0].Location.Path)
+	assert.Equal(t, "GA9000", diags[0].ID)
+	assert.Equal(t, "a19.go", diags[39].Location.Path)
+	assert.Equal(t, "GA9001", diags[39].ID)
+}
+
+func TestNewDriver_InvalidRules(t *testing.T) {
+	assert.Panics(t, func() { analysis.NewDriver(&recordingRule{}) })
+	assert.Panics(t, func() { analysis.NewDriver(&recordingRule{id: "R1"}, &recordingRule{id: "R1"}) })
+	assert.Panics(t, func() {
+		analysis.NewDriver(ruleFunc(func(ctx *analysis.RuleContext) {
+			ctx.RegisterNodeAction(func(*analysis.NodeContext) {})
+		}))
+	})
+}
+
+func TestDriver_GetDescriptors(t *testing.T) {
+	driver := analysis.NewDriver(testrules.PrintlnRule{}, emptyStringRule{})
+	assert.Equal(t, 2, len(driver.GetRules()))
+	assert.Equal(t, []diag.Descriptor{
+		{ID: "GA9000", Name: "no-println", Title: "Avoid println", DefaultSeverity: diag.SeverityWarning},
+		{ID: "GA9001", Name: "empty-string", DefaultSeverity: diag.SeverityInfo},
+	}, driver.GetDescriptors())
+}
+
+type ruleFunc func(ctx *analysis.RuleContext)
+
+func (f ruleFunc) Metadata() analysis.RuleInfo {
+	return analysis.RuleInfo{ID: "GA9003"}
+}
+
+func (f ruleFunc) Initialize(ctx *analysis.RuleContext) {
+	f(ctx)
+}
+
+// optionsRule reports functions with more statements than the max option
+type optionsRule struct{}
+
+type optionsRuleOptions struct {
+	Max int `json:"max"`
+}
+
+func (r optionsRule) Metadata() analysis.RuleInfo {
+	return analysis.RuleInfo{ID: "GA9004", DefaultSeverity: diag.SeverityWarning}
+}
+
+func (r optionsRule) Initialize(ctx *analysis.RuleContext) {
+	ctx.RegisterNodeAction(func(ctx *analysis.NodeContext) {
+		options := optionsRuleOptions{Max: 1}
+		if err := ctx.GetOptions(&options); err != nil {
+			panic(err)
+		}
+
+		body := ctx.Node.GetAstNode().(*ast.FuncDecl).Body
+		if len(body.List) > options.Max {
+			ctx.ReportAt(ctx.Node, "too many statements: %d > %d", len(body.List), options.Max)
+		}
+	}, syntaxkind.AstFuncDecl)
+}
+
+type testSettings map[string]analysis.RuleSettings
+
+func (s testSettings) GetRuleSettings(doc *syntax.Document, info analysis.RuleInfo) analysis.RuleSettings {
+	if settings, ok := s[doc.Path+":"+info.ID]; ok {
+		return settings
+	}
+	return analysis.GetDefaultSettings(info)
+}
+
+func TestDriver_Settings(t *testing.T) {
+	registry := syntax.NewDocumentRegistry()
+	src := "package a\n\nfunc f() {\n\tprintln(\"\")\n\tprintln()\n}\n"
+	a := addTestDocument(t, registry, "a.go", src)
+	b := addTestDocument(t, registry, "b.go", src)
+	c := addTestDocument(t, registry, "c.go", src)
+
+	driver := analysis.NewDriver(emptyStringRule{}, optionsRule{})
+	driver.Settings = testSettings{
+		"a.go:GA9001": {Severity: diag.SeverityError},
+		"a.go:GA9004": {Severity: diag.SeverityInfo, Options: map[string]interface{}{"max": 2}},
+		"b.go:GA9001": {Disabled: true},
+		"c.go:GA9004": {Severity: diag.SeverityWarning, Options: map[string]interface{}{"max": "x"}},
+	}
+
+	messages := []string{}
+	for _, d := range driver.AnalyzeDocuments([]*syntax.Document{a, b, c}) {
+		messages = append(messages, d.String())
+	}
+	if assert.Len(t, messages, 4) {
+		assert.Equal(t, "a.go[31..33): error GA9001: empty string literal", messages[0])
+		assert.Equal(t, "b.go[11..47): warning GA9004: too many statements: 2 > 1", messages[1])
+		assert.True(t, strings.HasPrefix(messages[2], "c.go[11..47): error GA0002: rule GA9004 failed: invalid options of rule GA9004: "))
+		assert.Equal(t, "c.go[31..33): info GA9001: empty string literal", messages[3])
+	}
+}
+
+func TestDriver_Suppressions(t *testing.T) {
+	asttest.RunAnalyzer(t, analysis.NewDriver(testrules.PrintlnRule{}, emptyStringRule{}), "testdata/suppression")
+}
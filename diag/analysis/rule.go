@@ -0,0 +1,66 @@
+package analysis
+
+import (
+	"go/token"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+)
+
+// Rule analyzes syntax trees and reports diagnostics. Rule registers actions in Initialize
+// and the driver calls them while walking the trees. Actions may be called concurrently for different files.
+type Rule interface {
+	Metadata() RuleInfo
+	Initialize(ctx *RuleContext)
+}
+
+// RuleInfo describes the rule and diagnostics it reports
+type RuleInfo = diag.Descriptor
+
+// NodeAction is called for syntax nodes of the registered kinds
+type NodeAction func(ctx *NodeContext)
+
+// TokenAction is called for tokens of the registered kinds
+type TokenAction func(ctx *TokenContext)
+
+// FileAction is called once per file
+type FileAction func(ctx *FileContext)
+
+// RuleContext is passed to Rule.Initialize to register actions of the rule
+type RuleContext struct {
+	rule   int
+	info   RuleInfo
+	driver *Driver
+}
+
+// GetRuleInfo returns metadata of the rule
+func (c *RuleContext) GetRuleInfo() RuleInfo {
+	return c.info
+}
+
+// RegisterNodeAction registers action which is called for each syntax node of the given kinds
+func (c *RuleContext) RegisterNodeAction(action NodeAction, kinds ...syntaxkind.AstKind) {
+	if len(kinds) == 0 {
+		panic("kinds are empty!")
+	}
+
+	for _, kind := range kinds {
+		c.driver.nodeActions[kind] = append(c.driver.nodeActions[kind], nodeAction{rule: c.rule, action: action})
+	}
+}
+
+// RegisterTokenAction registers action which is called for each token of the given kinds
+func (c *RuleContext) RegisterTokenAction(action TokenAction, kinds ...token.Token) {
+	if len(kinds) == 0 {
+		panic("kinds are empty!")
+	}
+
+	for _, kind := range kinds {
+		c.driver.tokenActions[kind] = append(c.driver.tokenActions[kind], tokenAction{rule: c.rule, action: action})
+	}
+}
+
+// RegisterFileEndAction registers action which is called after all nodes and tokens of the file are visited
+func (c *RuleContext) RegisterFileEndAction(action FileAction) {
+	c.driver.fileEndActions = append(c.driver.fileEndActions, fileAction{rule: c.rule, action: action})
+}
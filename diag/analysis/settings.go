@@ -0,0 +1,24 @@
+package analysis
+
+import (
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+)
+
+// RuleSettings are settings of the rule for a document
+type RuleSettings struct {
+	Disabled bool
+	Severity diag.Severity
+	// Options are rule specific options, rules read them with FileContext.GetOptions
+	Options map[string]interface{}
+}
+
+// SettingsProvider returns settings of the rule for the document. It is called concurrently for different documents.
+type SettingsProvider interface {
+	GetRuleSettings(doc *syntax.Document, info RuleInfo) RuleSettings
+}
+
+// GetDefaultSettings returns settings of the rule when no settings provider is set
+func GetDefaultSettings(info RuleInfo) RuleSettings {
+	return RuleSettings{Severity: info.DefaultSeverity}
+}
@@ -0,0 +1,285 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// Prefixes of suppression directive comments
+const (
+	IgnoreDirective     = "//goanalyzer:ignore"
+	FileIgnoreDirective = "//goanalyzer:file-ignore"
+)
+
+// SuppressionID is the id of diagnostics reported for malformed and unused suppression directives
+const SuppressionID = "GA0003"
+
+// SuppressionInfo describes diagnostics of suppression directives, their settings are returned
+// by the settings provider as for other rules, e.g. they can be disabled in the configuration
+var SuppressionInfo = RuleInfo{
+	ID:              SuppressionID,
+	Name:            "suppression",
+	Title:           "Malformed or unused suppression directive",
+	Help:            "Suppression directives must name suppressed rules and suppress at least one diagnostic.",
+	DefaultSeverity: diag.SeverityWarning,
+}
+
+// RuleFailureInfo describes diagnostics reported when an action of a rule panics
+var RuleFailureInfo = RuleInfo{
+	ID:              RuleFailureID,
+	Name:            "rule-failure",
+	Title:           "Rule failed",
+	DefaultSeverity: diag.SeverityError,
+}
+
+// Directive is the suppression directive comment. Directive //goanalyzer:ignore RULE[,RULE...] [reason]
+// suppresses diagnostics which start on its line if it follows code, otherwise diagnostics in the node
+// which starts after it, e.g. declaration or statement. Directive //goanalyzer:file-ignore [RULE[,RULE...] [reason]]
+// suppresses diagnostics of the rules in the whole file, all rules if no rules are given.
+type Directive struct {
+	// Path is the path of the document of the directive
+	Path string
+	// Rules are ids or names of the suppressed rules, empty if all rules are suppressed
+	Rules         []string
+	Justification string
+	// Comment is the span of the directive comment
+	Comment text.TextSpan
+	// Scope is the span where diagnostics are suppressed
+	Scope text.TextSpan
+	// File is true for file-ignore directives
+	File bool
+}
+
+// Matches checks if the directive suppresses the diagnostic of the rule
+func (d Directive) Matches(diagnostic diag.Diagnostic, info RuleInfo) bool {
+	if diagnostic.Location.Path != d.Path || !d.Scope.ContainsPos(diagnostic.Location.Span.Start()) {
+		return false
+	}
+	if len(d.Rules) == 0 {
+		return true
+	}
+
+	for _, rule := range d.Rules {
+		if rule == diagnostic.ID || (rule == info.Name && info.Name != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDirectives returns suppression directives of the document, malformed directives are returned as diagnostics
+func ParseDirectives(doc *syntax.Document) ([]Directive, []diag.Diagnostic) {
+	directives := []Directive{}
+	diags := []diag.Diagnostic{}
+	if doc.AstFile == nil {
+		return directives, diags
+	}
+
+	for _, group := range doc.AstFile.Comments {
+		for _, c := range group.List {
+			file := false
+			args := ""
+			switch {
+			case hasDirective(c.Text, FileIgnoreDirective):
+				file = true
+				args = c.Text[len(FileIgnoreDirective):]
+			case hasDirective(c.Text, IgnoreDirective):
+				args = c.Text[len(IgnoreDirective):]
+			default:
+				continue
+			}
+
+			span := text.NewTextSpanFromBounds(doc.GetOffset(c.Pos()), doc.GetOffset(c.End()))
+			d := Directive{Path: doc.Path, Comment: span, File: file}
+			fields := strings.Fields(args)
+			if len(fields) > 0 {
+				d.Rules = splitRules(fields[0])
+				d.Justification = strings.Join(fields[1:], " ")
+			}
+
+			if !file && len(d.Rules) == 0 {
+				location := diag.NewLocation(doc.Path, span)
+				diags = append(diags, diag.NewDiagnostic(SuppressionID, SuppressionInfo.DefaultSeverity, location,
+					"malformed suppression: %s requires rule ids", IgnoreDirective))
+				continue
+			}
+
+			if file {
+				d.Scope = text.NewTextSpan(0, doc.Text.Length())
+			} else {
+				d.Scope = getDirectiveScope(doc, span)
+			}
+			directives = append(directives, d)
+		}
+	}
+	return directives, diags
+}
+
+func hasDirective(comment string, directive string) bool {
+	if !strings.HasPrefix(comment, directive) {
+		return false
+	}
+	rest := comment[len(directive):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+func splitRules(str string) []string {
+	rules := []string{}
+	for _, rule := range strings.Split(str, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// getDirectiveScope returns the line of the directive if it follows code on the line,
+// otherwise span of the outermost node which starts at the next token
+func getDirectiveScope(doc *syntax.Document, comment text.TextSpan) text.TextSpan {
+	line := doc.Text.GetLineFromPosition(comment.Start())
+	lineSpan := doc.Text.GetLineSpan(line)
+	before := doc.Text.GetSubText(text.NewTextSpanFromBounds(lineSpan.Start(), comment.Start()))
+	if strings.TrimSpace(before) != "" {
+		return lineSpan
+	}
+
+	pos, ok := getNextTokenPos(doc, comment.End())
+	if !ok {
+		return text.NewTextSpan(comment.End(), 0)
+	}
+
+	var scope ast.Node
+	ast.Inspect(doc.AstFile, func(node ast.Node) bool {
+		if node == nil || scope != nil || node.End() <= pos || node.Pos() > pos {
+			return false
+		}
+		if _, ok := node.(*ast.File); !ok && node.Pos() == pos {
+			scope = node
+			return false
+		}
+		return true
+	})
+
+	if scope == nil {
+		return doc.Text.GetLineSpan(doc.Text.GetLineFromPosition(doc.GetOffset(pos)))
+	}
+	return text.NewTextSpanFromBounds(doc.GetOffset(scope.Pos()), doc.GetOffset(scope.End()))
+}
+
+func getNextTokenPos(doc *syntax.Document, offset int) (token.Pos, bool) {
+	src := doc.Text.String()
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(src)-offset)
+
+	var s scanner.Scanner
+	s.Init(file, []byte(src[offset:]), nil, 0)
+	pos, tok, _ := s.Scan()
+	if tok == token.EOF {
+		return token.NoPos, false
+	}
+	return doc.GetPos(offset + file.Offset(pos)), true
+}
+
+// applySuppressions marks suppressed diagnostics and reports unused directives of the rules which ran.
+// Each directive rule is checked separately, so unused rules of a directive with several rules are reported too.
+func applySuppressions(doc *syntax.Document, diags []diag.Diagnostic, rules []RuleInfo, enabled []bool, report func(diag.Diagnostic)) {
+	directives, malformed := ParseDirectives(doc)
+	for _, d := range malformed {
+		report(d)
+	}
+
+	infos := map[string]RuleInfo{}
+	for _, info := range rules {
+		infos[info.ID] = info
+	}
+
+	used := make([]map[string]bool, len(directives))
+	for i := range used {
+		used[i] = map[string]bool{}
+	}
+
+	for i := range diags {
+		d := &diags[i]
+		for j, directive := range directives {
+			info := infos[d.ID]
+			if !directive.Matches(*d, info) {
+				continue
+			}
+
+			d.Suppressions = append(d.Suppressions, diag.Suppression{Kind: diag.SuppressionInSource, Justification: directive.Justification})
+			for _, rule := range directive.Rules {
+				if rule == d.ID || rule == info.Name {
+					used[j][rule] = true
+				}
+			}
+			used[j][""] = true
+		}
+	}
+
+	names := map[string]bool{}
+	active := map[string]bool{}
+	for i, info := range rules {
+		names[info.ID] = true
+		active[info.ID] = enabled[i]
+		if info.Name != "" {
+			names[info.Name] = true
+			active[info.Name] = enabled[i]
+		}
+	}
+
+	for i, directive := range directives {
+		location := diag.NewLocation(doc.Path, directive.Comment)
+		if len(directive.Rules) == 0 && !used[i][""] {
+			d := diag.NewDiagnostic(SuppressionID, SuppressionInfo.DefaultSeverity, location, "unused suppression of all rules")
+			d.Tags = []diag.Tag{diag.TagUnnecessary}
+			d.Fixes = []diag.CodeFix{getRemoveDirectiveFix(doc, directive)}
+			report(d)
+			continue
+		}
+
+		unused := []string{}
+		for _, rule := range directive.Rules {
+			if rule == SuppressionID || rule == SuppressionInfo.Name {
+				// suppression diagnostics are reported after directives are applied
+				report(diag.NewDiagnostic(SuppressionID, SuppressionInfo.DefaultSeverity, location, "%s cannot be suppressed", rule))
+			} else if !names[rule] {
+				report(diag.NewDiagnostic(SuppressionID, SuppressionInfo.DefaultSeverity, location, "suppression of unknown rule %s", rule))
+			} else if active[rule] && !used[i][rule] {
+				unused = append(unused, rule)
+			}
+		}
+
+		for _, rule := range unused {
+			d := diag.NewDiagnostic(SuppressionID, SuppressionInfo.DefaultSeverity, location, "unused suppression of %s", rule)
+			d.Tags = []diag.Tag{diag.TagUnnecessary}
+			if len(unused) == len(directive.Rules) {
+				d.Fixes = []diag.CodeFix{getRemoveDirectiveFix(doc, directive)}
+			}
+			report(d)
+		}
+	}
+}
+
+// getRemoveDirectiveFix returns fix which removes the directive comment, the whole line is removed
+// if the comment is the only text on it, otherwise the comment and whitespace before it
+func getRemoveDirectiveFix(doc *syntax.Document, directive Directive) diag.CodeFix {
+	line := doc.Text.GetLineFromPosition(directive.Comment.Start())
+	lineSpan := doc.Text.GetLineSpan(line)
+	before := doc.Text.GetSubText(text.NewTextSpanFromBounds(lineSpan.Start(), directive.Comment.Start()))
+	after := doc.Text.GetSubText(text.NewTextSpanFromBounds(directive.Comment.End(), lineSpan.End()))
+
+	var span text.TextSpan
+	if strings.TrimSpace(before) == "" && strings.TrimSpace(after) == "" {
+		span = doc.Text.GetLineSpanIncludingLineBreak(line)
+	} else {
+		start := lineSpan.Start() + len(strings.TrimRight(before, " \t"))
+		span = text.NewTextSpanFromBounds(start, directive.Comment.End())
+	}
+	return diag.NewCodeFix("Remove suppression", text.NewTextChange(span, ""))
+}
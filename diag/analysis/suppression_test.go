@@ -0,0 +1,102 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDirectives(t *testing.T) {
+	src := `//goanalyzer:file-ignore
+
+package a
+
+var x = 1 //goanalyzer:ignore GA1,rule-2 some reason
+
+//goanalyzer:ignore GA3
+func f() {
+}
+
+//goanalyzer:ignored GA4
+//goanalyzer:ignore
+`
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", src)
+	directives, diags := analysis.ParseDirectives(doc)
+
+	texts := []string{}
+	for _, d := range directives {
+		texts = append(texts, doc.Text.GetSubText(d.Scope))
+	}
+	assert.Equal(t, []string{src, "var x = 1 //goanalyzer:ignore GA1,rule-2 some reason", "func f() {\n}"}, texts)
+
+	if assert.Len(t, directives, 3) {
+		assert.True(t, directives[0].File)
+		assert.Empty(t, directives[0].Rules)
+		assert.Equal(t, []string{"GA1", "rule-2"}, directives[1].Rules)
+		assert.Equal(t, "some reason", directives[1].Justification)
+		assert.Equal(t, "//goanalyzer:ignore GA3", doc.Text.GetSubText(directives[2].Comment))
+	}
+
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "a.go[154..173): warning GA0003: malformed suppression: //goanalyzer:ignore requires rule ids", diags[0].String())
+	}
+}
+
+func TestDriver_SuppressionSettings(t *testing.T) {
+	src := "package a\n\nvar x = \"\" //goanalyzer:ignore GA9001 empty\nvar y = 1 //goanalyzer:ignore GA9001\n"
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", src)
+
+	driver := analysis.NewDriver(emptyStringRule{})
+	diags := driver.Analyze(doc)
+	if assert.Len(t, diags, 2) {
+		assert.Equal(t, []diag.Suppression{{Kind: diag.SuppressionInSource, Justification: "empty"}}, diags[0].Suppressions)
+		assert.Equal(t, "a.go[65..91): warning GA0003: unused suppression of GA9001", diags[1].String())
+		assert.True(t, diags[1].HasTag(diag.TagUnnecessary))
+	}
+
+	driver.Settings = testSettings{"a.go:GA0003": {Severity: diag.SeverityError}}
+	diags = driver.Analyze(doc)
+	if assert.Len(t, diags, 2) {
+		assert.Equal(t, diag.SeverityError, diags[1].Severity)
+	}
+
+	driver.Settings = testSettings{"a.go:GA0003": {Disabled: true}}
+	assert.Len(t, driver.Analyze(doc), 1)
+
+	// suppressions of disabled rules are not reported as unused
+	driver.Settings = testSettings{"a.go:GA9001": {Disabled: true}}
+	assert.Empty(t, driver.Analyze(doc))
+}
+
+func TestDriver_SuppressionOfSuppressions(t *testing.T) {
+	src := "package a\n\nvar x = 1 //goanalyzer:ignore GA0003,suppression\n"
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", src)
+
+	messages := []string{}
+	for _, d := range analysis.NewDriver(emptyStringRule{}).Analyze(doc) {
+		messages = append(messages, d.String())
+	}
+	assert.Equal(t, []string{
+		"a.go[21..59): warning GA0003: GA0003 cannot be suppressed",
+		"a.go[21..59): warning GA0003: suppression cannot be suppressed",
+	}, messages)
+}
+
+func TestDirectiveMatches(t *testing.T) {
+	src := "package a\n\nvar x = \"\" //goanalyzer:ignore GA9001\n"
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", src)
+	directives, _ := analysis.ParseDirectives(doc)
+	if assert.Len(t, directives, 1) {
+		info := emptyStringRule{}.Metadata()
+		d := diag.NewDiagnostic("GA9001", diag.SeverityInfo, diag.NewLocation("a.go", text.NewTextSpan(19, 2)), "empty")
+		assert.True(t, directives[0].Matches(d, info))
+
+		// diagnostics of other documents at the same offsets are not suppressed
+		d.Location.Path = "b.go"
+		assert.False(t, directives[0].Matches(d, info))
+	}
+}
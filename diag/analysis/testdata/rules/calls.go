@@ -0,0 +1,10 @@
+package rules
+
+func f() {
+	[|println|]("hello") // want "avoid println"
+	print("")            // want "empty string literal"
+	g := func() {
+		[|println|]() // want "avoid println"
+	}
+	g()
+}
@@ -0,0 +1,27 @@
+package suppression
+
+func f() {
+	println("a") //goanalyzer:ignore GA9000 intended
+	println("b") [|//goanalyzer:ignore no-println,GA9001|]
+	[|println|]("c") // want "avoid println"
+
+	//goanalyzer:ignore GA9000 debugging
+	if true {
+		println("d")
+	}
+	print("") //goanalyzer:ignore GA9001
+	print("e") [|//goanalyzer:ignore GA9000|]
+	[|//goanalyzer:ignore GA9999 unknown|]
+	print("f")
+	[|//goanalyzer:ignore|]
+	print("g")
+}
+
+//goanalyzer:ignore GA9001 declarations are suppressed too
+var s = ""
+
+// g is suppressed with the doc comment
+//goanalyzer:ignore GA9000
+func g() {
+	println()
+}
@@ -0,0 +1,8 @@
+//goanalyzer:file-ignore GA9001 generated strings
+
+package suppression
+
+func h() {
+	print("")
+	[|println|]() // want "avoid println"
+}
@@ -0,0 +1,6 @@
+package suppression
+
+[|//goanalyzer:file-ignore|]
+
+func i() {
+}
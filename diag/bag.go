@@ -0,0 +1,76 @@
+package diag
+
+import (
+	"sort"
+	"sync"
+)
+
+// Bag collects diagnostics, it is safe for concurrent use
+type Bag struct {
+	mu    sync.Mutex
+	diags []Diagnostic
+}
+
+// NewBag creates new empty diagnostic bag
+func NewBag() *Bag {
+	return &Bag{}
+}
+
+// Add adds diagnostic to the bag
+func (b *Bag) Add(d Diagnostic) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.diags = append(b.diags, d)
+}
+
+// AddRange adds diagnostics to the bag
+func (b *Bag) AddRange(diags []Diagnostic) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.diags = append(b.diags, diags...)
+}
+
+// Len returns number of diagnostics in the bag
+func (b *Bag) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.diags)
+}
+
+// HasErrors checks if the bag contains diagnostics with error severity
+func (b *Bag) HasErrors() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, d := range b.diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDiagnostics returns copy of the bag diagnostics sorted by location,
+// so result does not depend on the order diagnostics were reported in
+func (b *Bag) GetDiagnostics() []Diagnostic {
+	b.mu.Lock()
+	diags := make([]Diagnostic, len(b.diags))
+	copy(diags, b.diags)
+	b.mu.Unlock()
+
+	SortDiagnostics(diags)
+	return diags
+}
+
+// Clear removes all diagnostics from the bag
+func (b *Bag) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.diags = nil
+}
+
+// SortDiagnostics sorts diagnostics by location, then by id and message
+func SortDiagnostics(diags []Diagnostic) {
+	sort.SliceStable(diags, func(i, j int) bool {
+		return diags[i].CompareTo(diags[j]) < 0
+	})
+}
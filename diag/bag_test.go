@@ -0,0 +1,58 @@
+package diag_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDiagnostic(path string, start int, id string, severity diag.Severity) diag.Diagnostic {
+	location := diag.NewLocation(path, text.NewTextSpan(start, 1))
+	return diag.NewDiagnostic(id, severity, location, "message of %s", id)
+}
+
+func Test_Bag_GetDiagnostics(t *testing.T) {
+	bag := diag.NewBag()
+	bag.Add(newTestDiagnostic("b.go", 0, "GA2", diag.SeverityWarning))
+	bag.AddRange([]diag.Diagnostic{
+		newTestDiagnostic("a.go", 5, "GA1", diag.SeverityInfo),
+		newTestDiagnostic("a.go", 5, "GA0", diag.SeverityInfo),
+		newTestDiagnostic("a.go", 1, "GA3", diag.SeverityInfo),
+	})
+
+	assert.Equal(t, 4, bag.Len())
+	assert.False(t, bag.HasErrors())
+
+	ids := []string{}
+	for _, d := range bag.GetDiagnostics() {
+		ids = append(ids, d.ID)
+	}
+	assert.Equal(t, []string{"GA3", "GA0", "GA1", "GA2"}, ids)
+
+	bag.Add(newTestDiagnostic("c.go", 0, "GA4", diag.SeverityError))
+	assert.True(t, bag.HasErrors())
+
+	bag.Clear()
+	assert.Equal(t, 0, bag.Len())
+	assert.Empty(t, bag.GetDiagnostics())
+}
+
+func Test_Bag_Concurrent(t *testing.T) {
+	bag := diag.NewBag()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				bag.Add(newTestDiagnostic(fmt.Sprintf("%d.go", i), j, "GA1", diag.SeverityInfo))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, bag.Len())
+}
@@ -0,0 +1,290 @@
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// Version is the version of the baseline file format
+const Version = 1
+
+// FileName is the default name of the baseline file
+const FileName = ".goanalyzer-baseline.json"
+
+// Justification is the justification of suppressions of diagnostics found in the baseline
+const Justification = "baseline"
+
+// Key identifies diagnostic in the baseline. Key does not contain line numbers,
+// so diagnostics are matched after code above them is changed.
+type Key struct {
+	Rule string `json:"rule"`
+	// File is slash separated path relative to the baseline root
+	File string `json:"file"`
+	// Declaration is the top level declaration which contains the diagnostic, e.g. "func (*T).f", "var x"
+	Declaration string `json:"declaration,omitempty"`
+	// Fingerprint is the hash of the rule and the normalized source lines of the diagnostic
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Entry is the baseline entry, Count is the number of diagnostics with the same key
+type Entry struct {
+	Key
+	Message string `json:"message,omitempty"`
+	Count   int    `json:"count"`
+}
+
+// Baseline records existing diagnostics, so only new diagnostics are reported
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+	// Root is the directory file paths are relative to
+	Root string `json:"-"`
+	// Dir is the directory relative diagnostic paths are relative to, the current directory if empty
+	Dir string `json:"-"`
+}
+
+// New creates baseline with entries of the diagnostics, suppressed diagnostics are skipped
+func New(root string, docs syntax.DocumentResolver, diags []diag.Diagnostic) *Baseline {
+	b := &Baseline{Version: Version, Entries: []Entry{}, Root: root}
+	b.Add(docs, diags)
+	return b
+}
+
+// Add adds entries of the diagnostics to the baseline, suppressed diagnostics are skipped
+func (b *Baseline) Add(docs syntax.DocumentResolver, diags []diag.Diagnostic) {
+	entries := map[Key]int{}
+	for i, e := range b.Entries {
+		entries[e.Key] = i
+	}
+
+	for _, d := range diags {
+		if d.IsSuppressed() {
+			continue
+		}
+
+		key := b.GetKey(docs, d)
+		if i, ok := entries[key]; ok {
+			b.Entries[i].Count++
+			continue
+		}
+		entries[key] = len(b.Entries)
+		b.Entries = append(b.Entries, Entry{Key: key, Message: d.GetMessage(), Count: 1})
+	}
+
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		return compareKeys(b.Entries[i].Key, b.Entries[j].Key) < 0
+	})
+}
+
+// Load reads baseline file, the root of the baseline is the file directory
+func Load(path string) (*Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	b, err := Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	b.Root = filepath.Dir(path)
+	return b, nil
+}
+
+// Read reads baseline in JSON format
+func Read(r io.Reader) (*Baseline, error) {
+	b := &Baseline{}
+	if err := json.NewDecoder(r).Decode(b); err != nil {
+		return nil, err
+	}
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported baseline version %d", b.Version)
+	}
+	return b, nil
+}
+
+// Save writes baseline file
+func (b *Baseline) Save(path string) error {
+	var buffer strings.Builder
+	if err := b.Write(&buffer); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(buffer.String()), 0644)
+}
+
+// Write writes baseline in JSON format
+func (b *Baseline) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(b)
+}
+
+// Apply marks diagnostics found in the baseline as suppressed with external suppression and
+// returns number of baseline diagnostics which were not found, e.g. because they were fixed
+func (b *Baseline) Apply(docs syntax.DocumentResolver, diags []diag.Diagnostic) int {
+	counts := map[Key]int{}
+	for _, e := range b.Entries {
+		counts[e.Key] += e.Count
+	}
+
+	for i := range diags {
+		d := &diags[i]
+		if d.IsSuppressed() {
+			continue
+		}
+
+		key := b.GetKey(docs, *d)
+		if counts[key] > 0 {
+			counts[key]--
+			d.Suppressions = append(d.Suppressions, diag.Suppression{Kind: diag.SuppressionExternal, Justification: Justification})
+		}
+	}
+
+	fixed := 0
+	for _, count := range counts {
+		fixed += count
+	}
+	return fixed
+}
+
+// GetKey returns baseline key of the diagnostic. If the document is not available the fingerprint
+// is computed from the message.
+func (b *Baseline) GetKey(docs syntax.DocumentResolver, d diag.Diagnostic) Key {
+	key := Key{Rule: d.ID, File: b.getRelativePath(d.Location.Path)}
+
+	var doc *syntax.Document
+	if docs != nil {
+		doc = docs.GetDocument(d.Location.Path)
+	}
+
+	content := d.GetMessage()
+	if doc != nil && d.Location.Span.End() <= doc.Text.Length() {
+		key.Declaration = GetDeclarationName(doc, d.Location.Span.Start())
+		content = getNormalizedLines(doc.Text, d.Location.Span)
+	}
+
+	hash := sha256.Sum256([]byte(d.ID + "\x00" + key.Declaration + "\x00" + content))
+	key.Fingerprint = hex.EncodeToString(hash[:8])
+	return key
+}
+
+func (b *Baseline) getRelativePath(path string) string {
+	if b.Root != "" {
+		abs := path
+		if b.Dir != "" && !filepath.IsAbs(abs) {
+			abs = filepath.Join(b.Dir, abs)
+		}
+		root, err1 := filepath.Abs(b.Root)
+		abs, err2 := filepath.Abs(abs)
+		if err1 == nil && err2 == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// getNormalizedLines returns source lines of the span with whitespace collapsed,
+// so diagnostics are matched after reindentation
+func getNormalizedLines(src *text.SourceText, span text.TextSpan) string {
+	start := src.GetLineFromPosition(span.Start())
+	end := src.GetLineFromPosition(span.End())
+	lines := []string{}
+	for line := start; line <= end; line++ {
+		lines = append(lines, strings.Join(strings.Fields(src.GetLineText(line)), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// GetDeclarationName returns name of the top level declaration which contains the offset,
+// e.g. "func f", "func (*T).f", "type T", "var x, y", or empty string if the offset is outside of declarations
+func GetDeclarationName(doc *syntax.Document, offset int) string {
+	if doc.AstFile == nil {
+		return ""
+	}
+
+	pos := doc.GetPos(offset)
+	for _, decl := range doc.AstFile.Decls {
+		start := decl.Pos()
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Doc != nil {
+			start = fn.Doc.Pos()
+		} else if gen, ok := decl.(*ast.GenDecl); ok && gen.Doc != nil {
+			start = gen.Doc.Pos()
+		}
+
+		if pos >= start && pos < decl.End() {
+			return getDeclName(decl)
+		}
+	}
+	return ""
+}
+
+func getDeclName(decl ast.Decl) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return "func " + d.Name.Name
+		}
+		return fmt.Sprintf("func (%s).%s", getTypeName(d.Recv.List[0].Type), d.Name.Name)
+	case *ast.GenDecl:
+		names := []string{}
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					names = append(names, name.Name)
+				}
+			}
+		}
+		if d.Tok == token.IMPORT || len(names) == 0 {
+			return d.Tok.String()
+		}
+		return d.Tok.String() + " " + strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func getTypeName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return "*" + getTypeName(e.X)
+	case *ast.ParenExpr:
+		return getTypeName(e.X)
+	case *ast.IndexExpr:
+		return getTypeName(e.X)
+	}
+	return "?"
+}
+
+func compareKeys(k1 Key, k2 Key) int {
+	for _, c := range [][2]string{{k1.File, k2.File}, {k1.Declaration, k2.Declaration}, {k1.Rule, k2.Rule}, {k1.Fingerprint, k2.Fingerprint}} {
+		if c[0] != c[1] {
+			if c[0] < c[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,197 @@
+package baseline_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/baseline"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+const srcV1 = `package a
+
+func f() {
+	println("a")
+}
+
+func (t *T) g() {
+	println("a")
+	println("b")
+}
+`
+
+// srcV2 shifts lines, reindents f, fixes println("b") and adds new println("c")
+const srcV2 = `package a
+
+import "fmt"
+
+// f prints
+func f() {
+		println("a")
+}
+
+func (t *T) g() {
+	println("a")
+	println("c")
+	fmt.Println()
+}
+`
+
+// getPrintlnDiagnostics reports each println call
+func getPrintlnDiagnostics(doc *syntax.Document) []diag.Diagnostic {
+	diags := []diag.Diagnostic{}
+	src := doc.Text.String()
+	for offset := 0; ; {
+		i := strings.Index(src[offset:], "println(")
+		if i < 0 {
+			return diags
+		}
+		offset += i
+		location := diag.NewLocation(doc.Path, text.NewTextSpan(offset, len("println")))
+		diags = append(diags, diag.NewDiagnostic("GA9000", diag.SeverityWarning, location, "avoid println"))
+		offset++
+	}
+}
+
+func addTestDocument(t *testing.T, registry *syntax.DocumentRegistry, path string, src string) *syntax.Document {
+	doc, err := registry.AddDocument(path, src)
+	assert.NoError(t, err)
+	return doc
+}
+
+func TestBaseline(t *testing.T) {
+	registry := syntax.NewDocumentRegistry()
+	doc := addTestDocument(t, registry, "a.go", srcV1)
+	b := baseline.New("", registry, getPrintlnDiagnostics(doc))
+
+	if assert.Len(t, b.Entries, 3) {
+		assert.Equal(t, "func (*T).g", b.Entries[0].Declaration)
+		assert.Equal(t, "func (*T).g", b.Entries[1].Declaration)
+		assert.Equal(t, "func f", b.Entries[2].Declaration)
+		assert.Equal(t, "a.go", b.Entries[2].File)
+		assert.Equal(t, "GA9000", b.Entries[2].Rule)
+		assert.Equal(t, "avoid println", b.Entries[2].Message)
+		assert.Equal(t, 1, b.Entries[2].Count)
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, b.Write(&buffer))
+	b, err := baseline.Read(&buffer)
+	assert.NoError(t, err)
+
+	doc = addTestDocument(t, registry, "a.go", srcV2)
+	diags := getPrintlnDiagnostics(doc)
+	fixed := b.Apply(registry, diags)
+	assert.Equal(t, 1, fixed)
+
+	reported := []string{}
+	for _, d := range diags {
+		if d.IsSuppressed() {
+			assert.Equal(t, []diag.Suppression{{Kind: diag.SuppressionExternal, Justification: baseline.Justification}}, d.Suppressions)
+		} else {
+			reported = append(reported, doc.Text.GetLineText(doc.Text.GetLineFromPosition(d.Location.Span.Start())))
+		}
+	}
+	assert.Equal(t, []string{"\tprintln(\"c\")"}, reported)
+}
+
+func TestBaseline_Counts(t *testing.T) {
+	registry := syntax.NewDocumentRegistry()
+	src := "package a\n\nfunc f() {\n\tprintln()\n\tprintln()\n}\n"
+	doc := addTestDocument(t, registry, "a.go", src)
+	b := baseline.New("", registry, getPrintlnDiagnostics(doc))
+	if assert.Len(t, b.Entries, 1) {
+		assert.Equal(t, 2, b.Entries[0].Count)
+	}
+
+	doc = addTestDocument(t, registry, "a.go", strings.Replace(src, "println()\n", "println()\n\tprintln()\n", 1))
+	diags := getPrintlnDiagnostics(doc)
+	assert.Equal(t, 0, b.Apply(registry, diags))
+	assert.Equal(t, 1, len(diags)-countSuppressed(diags))
+}
+
+func countSuppressed(diags []diag.Diagnostic) int {
+	count := 0
+	for _, d := range diags {
+		if d.IsSuppressed() {
+			count++
+		}
+	}
+	return count
+}
+
+func TestBaseline_SaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "baseline")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	registry := syntax.NewDocumentRegistry()
+	path := filepath.Join(dir, "pkg", "a.go")
+	doc := addTestDocument(t, registry, path, srcV1)
+	diags := getPrintlnDiagnostics(doc)
+	diags[0].Suppressions = []diag.Suppression{{Kind: diag.SuppressionInSource}}
+
+	b := baseline.New(dir, registry, diags)
+	assert.Len(t, b.Entries, 2)
+	assert.Equal(t, "pkg/a.go", b.Entries[0].File)
+
+	baselinePath := filepath.Join(dir, baseline.FileName)
+	assert.NoError(t, b.Save(baselinePath))
+	loaded, err := baseline.Load(baselinePath)
+	if assert.NoError(t, err) {
+		assert.Equal(t, b.Entries, loaded.Entries)
+		assert.Equal(t, dir, loaded.Root)
+		assert.Equal(t, 0, loaded.Apply(registry, diags))
+		assert.Equal(t, 3, countSuppressed(diags))
+	}
+
+	_, err = baseline.Read(strings.NewReader(`{"version": 2}`))
+	assert.EqualError(t, err, "unsupported baseline version 2")
+}
+
+func TestBaseline_Dir(t *testing.T) {
+	registry := syntax.NewDocumentRegistry()
+	doc := addTestDocument(t, registry, "a.go", srcV1)
+	diags := getPrintlnDiagnostics(doc)
+
+	root := filepath.Join("testdata", "module")
+	b := &baseline.Baseline{Version: baseline.Version, Entries: []baseline.Entry{}, Root: root}
+	b.Add(registry, diags)
+	assert.Equal(t, "a.go", b.Entries[0].File)
+
+	b = &baseline.Baseline{Version: baseline.Version, Entries: []baseline.Entry{}, Root: root, Dir: filepath.Join(root, "pkg")}
+	b.Add(registry, diags)
+	assert.Equal(t, "pkg/a.go", b.Entries[0].File)
+}
+
+func TestGetDeclarationName(t *testing.T) {
+	src := `package a
+
+import "fmt"
+
+// T is a type
+type T struct{}
+
+var x, y = 1, 2
+
+func (T) f() {}
+`
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", src)
+	getName := func(str string) string {
+		return baseline.GetDeclarationName(doc, strings.Index(src, str))
+	}
+	assert.Equal(t, "", getName("package"))
+	assert.Equal(t, "import", getName(`"fmt"`))
+	assert.Equal(t, "type T", getName("// T is"))
+	assert.Equal(t, "var x, y", getName("2"))
+	assert.Equal(t, "func (T).f", getName("f()"))
+}
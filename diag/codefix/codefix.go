@@ -0,0 +1,222 @@
+package codefix
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// Filter selects diagnostics whose fixes are applied
+type Filter func(d diag.Diagnostic) bool
+
+// RuleFilter selects diagnostics of the rules, all diagnostics are selected if no rules are given
+func RuleFilter(rules ...string) Filter {
+	return func(d diag.Diagnostic) bool {
+		if len(rules) == 0 {
+			return true
+		}
+		for _, rule := range rules {
+			if rule == d.ID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Rejection is the fix which was not applied
+type Rejection struct {
+	Diagnostic diag.Diagnostic
+	Fix        diag.CodeFix
+	Reason     string
+}
+
+// String returns string representation of the rejection
+func (r Rejection) String() string {
+	return fmt.Sprintf("%s: %s", r.Diagnostic.String(), r.Reason)
+}
+
+// Result is the result of applying fixes to the document
+type Result struct {
+	Document *syntax.Document
+	// Fixed is the document text with applied fixes, it is the original text if no fixes were applied
+	Fixed *text.SourceText
+	// Applied are diagnostics whose fixes were applied
+	Applied  []diag.Diagnostic
+	Rejected []Rejection
+}
+
+// IsChanged checks if any fix was applied
+func (r *Result) IsChanged() bool {
+	return len(r.Applied) > 0 && r.Fixed.String() != r.Document.Text.String()
+}
+
+type candidate struct {
+	diagnostic diag.Diagnostic
+	fix        diag.CodeFix
+}
+
+// ApplyToDocument applies the first fix of each selected diagnostic of the document. Fixes are merged
+// in the diagnostics order, a fix whose changes overlap with changes of an already merged fix is rejected,
+// identical changes of different fixes are applied once. The fixed text always parses without errors:
+// fixes which break the syntax are rejected and no fixes are applied to documents with syntax errors.
+// Suppressed diagnostics, diagnostics of other documents and diagnostics without fixes are skipped.
+func ApplyToDocument(doc *syntax.Document, diags []diag.Diagnostic, filter Filter) *Result {
+	result := &Result{Document: doc, Fixed: doc.Text}
+	candidates := []candidate{}
+	for _, d := range sortDiagnostics(diags) {
+		if d.Location.Path != doc.Path || d.IsSuppressed() || len(d.Fixes) == 0 || (filter != nil && !filter(d)) {
+			continue
+		}
+
+		fix := d.Fixes[0]
+		if err := text.ValidateTextChanges(fix.Changes, doc.Text.Length()); err != nil {
+			result.Rejected = append(result.Rejected, Rejection{Diagnostic: d, Fix: fix, Reason: fmt.Sprintf("invalid fix: %v", err)})
+			continue
+		}
+		if len(doc.Diagnostics) > 0 {
+			result.Rejected = append(result.Rejected, Rejection{Diagnostic: d, Fix: fix, Reason: "source has syntax errors"})
+			continue
+		}
+		candidates = append(candidates, candidate{diagnostic: d, fix: fix})
+	}
+
+	accepted, changes := mergeFixes(result, candidates)
+	fixed, err := doc.Text.WithChanges(changes...)
+	if err != nil {
+		reject(result, accepted, fmt.Sprintf("cannot apply fixes: %v", err))
+		return result
+	}
+
+	if !checkSyntax(doc, fixed) {
+		// find fixes which break the syntax themselves and merge the rest again
+		valid := []candidate{}
+		for _, c := range accepted {
+			single, err := doc.Text.WithChanges(c.fix.Changes...)
+			if err == nil && checkSyntax(doc, single) {
+				valid = append(valid, c)
+			} else {
+				result.Rejected = append(result.Rejected, Rejection{Diagnostic: c.diagnostic, Fix: c.fix, Reason: "fix produces syntax errors"})
+			}
+		}
+
+		accepted, changes = mergeFixes(result, valid)
+		fixed, err = doc.Text.WithChanges(changes...)
+		if err != nil {
+			reject(result, accepted, fmt.Sprintf("cannot apply fixes: %v", err))
+			return result
+		}
+
+		if !checkSyntax(doc, fixed) {
+			reject(result, accepted, "fixes together produce syntax errors")
+			accepted, fixed = nil, doc.Text
+		}
+	}
+
+	for _, c := range accepted {
+		result.Applied = append(result.Applied, c.diagnostic)
+	}
+	result.Fixed = fixed
+	return result
+}
+
+// ApplyAll applies fixes of the selected diagnostics to their documents, e.g. fixes all occurrences
+// of a rule in a package or module. Results are sorted by document path, documents without fixes are skipped.
+func ApplyAll(docs syntax.DocumentResolver, diags []diag.Diagnostic, filter Filter) []*Result {
+	paths := []string{}
+	byPath := map[string][]diag.Diagnostic{}
+	for _, d := range diags {
+		if len(d.Fixes) == 0 || d.IsSuppressed() || (filter != nil && !filter(d)) {
+			continue
+		}
+		if _, ok := byPath[d.Location.Path]; !ok {
+			paths = append(paths, d.Location.Path)
+		}
+		byPath[d.Location.Path] = append(byPath[d.Location.Path], d)
+	}
+	sort.Strings(paths)
+
+	results := []*Result{}
+	for _, path := range paths {
+		doc := docs.GetDocument(path)
+		if doc == nil {
+			continue
+		}
+		results = append(results, ApplyToDocument(doc, byPath[path], filter))
+	}
+	return results
+}
+
+// reject adds the fixes to the rejected fixes of the result with the reason
+func reject(result *Result, candidates []candidate, reason string) {
+	for _, c := range candidates {
+		result.Rejected = append(result.Rejected, Rejection{Diagnostic: c.diagnostic, Fix: c.fix, Reason: reason})
+	}
+}
+
+// mergeFixes returns fixes which do not conflict with previous fixes and their changes without duplicates,
+// conflicting fixes are added to the rejected fixes of the result
+func mergeFixes(result *Result, candidates []candidate) ([]candidate, []text.TextChange) {
+	accepted := []candidate{}
+	changes := []text.TextChange{}
+	owners := []diag.Diagnostic{}
+	for _, c := range candidates {
+		added := []text.TextChange{}
+		conflict := -1
+		for _, change := range c.fix.Changes {
+			duplicate := false
+			for i, prev := range changes {
+				if prev == change {
+					duplicate = true
+					break
+				}
+				if text.ValidateTextChanges([]text.TextChange{prev, change}, result.Document.Text.Length()) != nil {
+					conflict = i
+					break
+				}
+			}
+			if conflict >= 0 {
+				break
+			}
+			if !duplicate {
+				added = append(added, change)
+			}
+		}
+
+		if conflict >= 0 {
+			owner := owners[conflict]
+			result.Rejected = append(result.Rejected, Rejection{
+				Diagnostic: c.diagnostic,
+				Fix:        c.fix,
+				Reason:     fmt.Sprintf("conflicts with fix of %s at %v", owner.ID, owner.Location),
+			})
+			continue
+		}
+
+		accepted = append(accepted, c)
+		for _, change := range added {
+			changes = append(changes, change)
+			owners = append(owners, c.diagnostic)
+		}
+	}
+	return accepted, changes
+}
+
+// checkSyntax checks that the fixed text parses without errors
+func checkSyntax(doc *syntax.Document, fixed *text.SourceText) bool {
+	if fixed == doc.Text {
+		return true
+	}
+	_, _, diags := syntax.ParseFile(token.NewFileSet(), doc.Path, fixed.String())
+	return len(diags) == 0
+}
+
+func sortDiagnostics(diags []diag.Diagnostic) []diag.Diagnostic {
+	sorted := append([]diag.Diagnostic{}, diags...)
+	diag.SortDiagnostics(sorted)
+	return sorted
+}
@@ -0,0 +1,149 @@
+package codefix_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/codefix"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSrc = `package a
+
+func f() {
+	println("a")
+	println("b")
+}
+`
+
+func addTestDocument(t *testing.T, registry *syntax.DocumentRegistry, path string, src string) *syntax.Document {
+	doc, err := registry.AddDocument(path, src)
+	assert.NoError(t, err)
+	return doc
+}
+
+// newFixDiagnostic creates diagnostic at the first occurrence of str with fix which replaces it
+func newFixDiagnostic(doc *syntax.Document, id string, str string, newText string) diag.Diagnostic {
+	span := text.NewTextSpan(strings.Index(doc.Text.String(), str), len(str))
+	d := diag.NewDiagnostic(id, diag.SeverityWarning, diag.NewLocation(doc.Path, span), "replace %s", str)
+	d.Fixes = []diag.CodeFix{diag.NewCodeFix("Replace", text.NewTextChange(span, newText))}
+	return d
+}
+
+// newImportDiagnostic creates diagnostic with fix which replaces str and adds fmt import
+func newImportDiagnostic(doc *syntax.Document, str string, newText string) diag.Diagnostic {
+	d := newFixDiagnostic(doc, "GA9001", str, newText)
+	importChange := text.NewTextChange(text.NewTextSpan(len("package a\n"), 0), "\nimport \"fmt\"\n")
+	d.Fixes[0].Changes = append(d.Fixes[0].Changes, importChange)
+	return d
+}
+
+func getRejections(result *codefix.Result) []string {
+	rejections := []string{}
+	for _, r := range result.Rejected {
+		rejections = append(rejections, r.String())
+	}
+	return rejections
+}
+
+func TestApplyToDocument(t *testing.T) {
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", testSrc)
+	diags := []diag.Diagnostic{
+		newImportDiagnostic(doc, `println("b")`, `fmt.Println("b")`),
+		newImportDiagnostic(doc, `println("a")`, `fmt.Println("a")`),
+		newFixDiagnostic(doc, "GA9000", `"a"`, `"A"`),
+		newFixDiagnostic(doc, "GA9000", "func f", "func g"),
+	}
+	diags[3].Suppressions = []diag.Suppression{{Kind: diag.SuppressionInSource}}
+
+	result := codefix.ApplyToDocument(doc, diags, nil)
+	assert.True(t, result.IsChanged())
+	assert.Equal(t, `package a
+
+import "fmt"
+
+func f() {
+	fmt.Println("a")
+	fmt.Println("b")
+}
+`, result.Fixed.String())
+	assert.Len(t, result.Applied, 2)
+	assert.Equal(t, []string{
+		`a.go[31..34): warning GA9000: replace "a": conflicts with fix of GA9001 at a.go[23..35)`,
+	}, getRejections(result))
+
+	result = codefix.ApplyToDocument(doc, diags, codefix.RuleFilter("GA9000"))
+	assert.Equal(t, strings.Replace(testSrc, `"a"`, `"A"`, 1), result.Fixed.String())
+	assert.Empty(t, result.Rejected)
+}
+
+func TestApplyToDocument_SyntaxErrors(t *testing.T) {
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", testSrc)
+	diags := []diag.Diagnostic{
+		newFixDiagnostic(doc, "GA9000", `println("a")`, `println("a"`),
+		newFixDiagnostic(doc, "GA9001", `"b"`, `"B"`),
+	}
+
+	result := codefix.ApplyToDocument(doc, diags, nil)
+	assert.Equal(t, strings.Replace(testSrc, `"b"`, `"B"`, 1), result.Fixed.String())
+	assert.Equal(t, []string{
+		`a.go[23..35): warning GA9000: replace println("a"): fix produces syntax errors`,
+	}, getRejections(result))
+
+	// fixes which are valid alone but break the syntax together
+	doc = addTestDocument(t, syntax.NewDocumentRegistry(), "b.go", "package a\n\nvar x = 1\n")
+	diags = []diag.Diagnostic{
+		newFixDiagnostic(doc, "GA9000", "x", "x int"),
+		newFixDiagnostic(doc, "GA9001", "=", "int ="),
+	}
+	result = codefix.ApplyToDocument(doc, diags, nil)
+	assert.False(t, result.IsChanged())
+	assert.True(t, result.Fixed == doc.Text)
+	assert.Len(t, result.Rejected, 2)
+	assert.Equal(t, "fixes together produce syntax errors", result.Rejected[0].Reason)
+}
+
+func TestApplyToDocument_DocumentSyntaxErrors(t *testing.T) {
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", "package a\n\nvar x = 1\nvar y = \n")
+	assert.Error(t, err)
+	result := codefix.ApplyToDocument(doc, []diag.Diagnostic{newFixDiagnostic(doc, "GA9000", "x", "z")}, nil)
+	assert.False(t, result.IsChanged())
+	assert.Equal(t, []string{"a.go[15..16): warning GA9000: replace x: source has syntax errors"}, getRejections(result))
+}
+
+func TestApplyToDocument_InvalidFix(t *testing.T) {
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", testSrc)
+	d := newFixDiagnostic(doc, "GA9000", "func", "fn")
+	d.Fixes[0].Changes = append(d.Fixes[0].Changes, text.NewTextChange(text.NewTextSpan(1000, 1), ""))
+
+	result := codefix.ApplyToDocument(doc, []diag.Diagnostic{d}, nil)
+	assert.False(t, result.IsChanged())
+	if assert.Len(t, result.Rejected, 1) {
+		assert.Contains(t, result.Rejected[0].Reason, "invalid fix: ")
+	}
+}
+
+func TestApplyAll(t *testing.T) {
+	registry := syntax.NewDocumentRegistry()
+	a := addTestDocument(t, registry, "a.go", testSrc)
+	b := addTestDocument(t, registry, "b.go", testSrc)
+	c := addTestDocument(t, registry, "c.go", testSrc)
+	diags := []diag.Diagnostic{
+		newFixDiagnostic(b, "GA9000", "println", "print"),
+		newFixDiagnostic(a, "GA9000", "println", "print"),
+		newFixDiagnostic(c, "GA9001", "println", "print"),
+		newFixDiagnostic(a, "GA9000", `"b"`, `"B"`),
+	}
+
+	results := codefix.ApplyAll(registry, diags, codefix.RuleFilter("GA9000"))
+	if assert.Len(t, results, 2) {
+		assert.True(t, results[0].Document == a)
+		assert.Len(t, results[0].Applied, 2)
+		assert.Equal(t, "package a\n\nfunc f() {\n\tprint(\"a\")\n\tprintln(\"B\")\n}\n", results[0].Fixed.String())
+		assert.True(t, results[1].Document == b)
+		assert.Len(t, results[1].Applied, 1)
+	}
+}
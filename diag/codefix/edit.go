@@ -0,0 +1,42 @@
+package codefix
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// Replace returns change which replaces the syntax element with the new text
+func Replace(doc *syntax.Document, elmt syntax.Element, newText string) text.TextChange {
+	return text.NewTextChange(doc.GetSpan(elmt), newText)
+}
+
+// Remove returns change which removes the syntax element
+func Remove(doc *syntax.Document, elmt syntax.Element) text.TextChange {
+	return Replace(doc, elmt, "")
+}
+
+// InsertBefore returns change which inserts the text before the syntax element
+func InsertBefore(doc *syntax.Document, elmt syntax.Element, newText string) text.TextChange {
+	return text.NewTextChange(text.NewTextSpan(doc.GetSpan(elmt).Start(), 0), newText)
+}
+
+// InsertAfter returns change which inserts the text after the syntax element
+func InsertAfter(doc *syntax.Document, elmt syntax.Element, newText string) text.TextChange {
+	return text.NewTextChange(text.NewTextSpan(doc.GetSpan(elmt).End(), 0), newText)
+}
+
+// ReplaceWithNode returns change which replaces the syntax element with the formatted ast node,
+// so rules can rewrite syntax trees instead of building text. The node should be created by the rule
+// and have no positions, positions of the document nodes are not valid for the printer.
+func ReplaceWithNode(doc *syntax.Document, elmt syntax.Element, node ast.Node) (text.TextChange, error) {
+	var buffer bytes.Buffer
+	if err := format.Node(&buffer, token.NewFileSet(), node); err != nil {
+		return text.TextChange{}, err
+	}
+	return Replace(doc, elmt, buffer.String()), nil
+}
@@ -0,0 +1,51 @@
+package codefix_test
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/codefix"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func findCall(node syntax.Node) syntax.Node {
+	if _, ok := node.GetAstNode().(*ast.CallExpr); ok {
+		return node
+	}
+	for _, elmt := range node.GetElements() {
+		if child, ok := elmt.(syntax.Node); ok {
+			if call := findCall(child); call != nil {
+				return call
+			}
+		}
+	}
+	return nil
+}
+
+func TestEdits(t *testing.T) {
+	doc := addTestDocument(t, syntax.NewDocumentRegistry(), "a.go", testSrc)
+	call := findCall(doc.Root)
+	fun := call.GetElements()[0]
+
+	apply := func(changes ...text.TextChange) string {
+		fixed, err := doc.Text.WithChanges(changes...)
+		assert.NoError(t, err)
+		return fixed.GetLineText(3)
+	}
+
+	assert.Equal(t, "\tprint(\"a\")", apply(codefix.Replace(doc, fun, "print")))
+	assert.Equal(t, "\t(\"a\")", apply(codefix.Remove(doc, fun)))
+	assert.Equal(t, "\tx := println(\"a\")", apply(codefix.InsertBefore(doc, call, "x := ")))
+	assert.Equal(t, "\tprintln(\"a\");", apply(codefix.InsertAfter(doc, call, ";")))
+
+	node := &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent("fmt"), Sel: ast.NewIdent("Println")},
+		Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: `"a"`}},
+	}
+	change, err := codefix.ReplaceWithNode(doc, call, node)
+	assert.NoError(t, err)
+	assert.Equal(t, "\tfmt.Println(\"a\")", apply(change))
+}
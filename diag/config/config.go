@@ -0,0 +1,222 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+)
+
+// AllRules is the rules key which configures all rules, settings of specific rules take precedence over it
+const AllRules = "*"
+
+// Config configures rules, settings can be changed for files matching overrides
+type Config struct {
+	// Path is the path of the configuration file, it is empty for the default configuration
+	Path string
+	// Root is the directory file patterns are relative to
+	Root string
+	// Dir is the directory relative document paths are relative to, the current directory if empty
+	Dir       string
+	Rules     map[string]RuleConfig
+	Overrides []Override
+}
+
+// RuleConfig configures the rule, nil fields do not change the rule settings
+type RuleConfig struct {
+	Enabled  *bool
+	Severity *diag.Severity
+	Options  map[string]interface{}
+}
+
+// Override changes rule settings of the matching files. If both Files and Generated are set
+// a file must match both of them.
+type Override struct {
+	// Files are slash separated glob patterns relative to the root. ** matches any number of directories.
+	// Pattern matches a file if it matches the file path or one of its parent directories.
+	Files []string
+	// Generated matches generated files which have "// Code generated ... DO NOT EDIT." comment
+	Generated bool
+	Rules     map[string]RuleConfig
+}
+
+// Error is the error of the configuration file which names the offending key
+type Error struct {
+	Path    string
+	Key     string
+	Message string
+}
+
+// Error returns the error message
+func (e *Error) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s: %s", e.Path, e.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", e.Path, e.Key, e.Message)
+}
+
+// New creates default configuration which uses default settings of the rules
+func New(root string) *Config {
+	return &Config{
+		Root:  root,
+		Rules: map[string]RuleConfig{},
+	}
+}
+
+// Validate checks that configured rules exist, rules can be referenced by id or name
+func (c *Config) Validate(rules []analysis.RuleInfo) error {
+	names := map[string]bool{AllRules: true}
+	for _, info := range rules {
+		names[info.ID] = true
+		if info.Name != "" {
+			names[info.Name] = true
+		}
+	}
+
+	if err := c.validateRules("rules", c.Rules, names); err != nil {
+		return err
+	}
+	for i, o := range c.Overrides {
+		if err := c.validateRules(fmt.Sprintf("overrides[%d].rules", i), o.Rules, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Config) validateRules(key string, rules map[string]RuleConfig, names map[string]bool) error {
+	keys := []string{}
+	for name := range rules {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	for _, name := range keys {
+		if !names[name] {
+			return &Error{Path: c.getPath(), Key: key + "." + name, Message: "unknown rule"}
+		}
+	}
+	return nil
+}
+
+func (c *Config) getPath() string {
+	if c.Path == "" {
+		return "config"
+	}
+	return c.Path
+}
+
+// GetRuleSettings returns settings of the rule for the document. Settings of the matching overrides
+// are applied in order after the top level settings.
+func (c *Config) GetRuleSettings(doc *syntax.Document, info analysis.RuleInfo) analysis.RuleSettings {
+	settings := analysis.GetDefaultSettings(info)
+	applyRules(&settings, c.Rules, info)
+
+	path, inRoot := c.getRelativePath(doc.Path)
+	generated := IsGenerated(doc)
+	for _, o := range c.Overrides {
+		if o.matches(path, inRoot, generated) {
+			applyRules(&settings, o.Rules, info)
+		}
+	}
+	return settings
+}
+
+// getRelativePath returns slash separated path relative to the root, relative paths are relative to Dir
+func (c *Config) getRelativePath(path string) (string, bool) {
+	if c.Dir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(c.Dir, path)
+	}
+	root, err := filepath.Abs(c.Root)
+	if err != nil {
+		return "", false
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return "", false
+	}
+	if path, err = filepath.Rel(root, path); err != nil {
+		return "", false
+	}
+
+	path = filepath.ToSlash(path)
+	if path == ".." || strings.HasPrefix(path, "../") {
+		return "", false
+	}
+	return path, true
+}
+
+func (o *Override) matches(path string, inRoot bool, generated bool) bool {
+	if o.Generated && !generated {
+		return false
+	}
+	if len(o.Files) == 0 {
+		return o.Generated
+	}
+	if !inRoot {
+		return false
+	}
+
+	for _, pattern := range o.Files {
+		if MatchFiles(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func applyRules(settings *analysis.RuleSettings, rules map[string]RuleConfig, info analysis.RuleInfo) {
+	keys := []string{AllRules, info.Name, info.ID}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if rule, ok := rules[key]; ok {
+			applyRule(settings, rule)
+		}
+	}
+}
+
+func applyRule(settings *analysis.RuleSettings, rule RuleConfig) {
+	if rule.Enabled != nil {
+		settings.Disabled = !*rule.Enabled
+	}
+	if rule.Severity != nil {
+		settings.Severity = *rule.Severity
+	}
+	if len(rule.Options) > 0 {
+		options := map[string]interface{}{}
+		for key, value := range settings.Options {
+			options[key] = value
+		}
+		for key, value := range rule.Options {
+			options[key] = value
+		}
+		settings.Options = options
+	}
+}
+
+var generatedRx = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// IsGenerated checks if the document has "// Code generated ... DO NOT EDIT." comment before the package clause
+func IsGenerated(doc *syntax.Document) bool {
+	if doc.AstFile == nil {
+		return false
+	}
+
+	for _, group := range doc.AstFile.Comments {
+		if group.Pos() > doc.AstFile.Package {
+			break
+		}
+		for _, c := range group.List {
+			if generatedRx.MatchString(c.Text) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package config_test
+
+import (
+	"go/token"
+	"path/filepath"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/config"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	printlnInfo  = analysis.RuleInfo{ID: "GA9000", Name: "no-println", DefaultSeverity: diag.SeverityInfo}
+	longFuncInfo = analysis.RuleInfo{ID: "GA9001", Name: "long-func", DefaultSeverity: diag.SeverityWarning}
+	otherInfo    = analysis.RuleInfo{ID: "GA9002", DefaultSeverity: diag.SeverityError}
+)
+
+const generatedSrc = "// Code generated by tool. DO NOT EDIT.\n\npackage a\n"
+
+func newTestDocument(t *testing.T, path string, src string) *syntax.Document {
+	doc, err := syntax.NewDocumentRegistry().AddDocument(path, src)
+	assert.NoError(t, err)
+	return doc
+}
+
+func checkRuleSettings(t *testing.T, c *config.Config, doc *syntax.Document, info analysis.RuleInfo, expected analysis.RuleSettings) {
+	t.Helper()
+	assert.Equal(t, expected, c.GetRuleSettings(doc, info), "%s %s", doc.Path, info.ID)
+}
+
+func checkConfigSettings(t *testing.T, c *config.Config) {
+	root := c.Root
+	main := newTestDocument(t, filepath.Join(root, "main.go"), "package main\n")
+	cmd := newTestDocument(t, filepath.Join(root, "cmd", "tool", "main.go"), "package main\n")
+	internal := newTestDocument(t, filepath.Join(root, "internal", "x", "a.go"), "package x\n")
+	test := newTestDocument(t, filepath.Join(root, "a", "a_test.go"), "package a\n")
+	generated := newTestDocument(t, filepath.Join(root, "cmd", "a.pb.go"), generatedSrc)
+	outside := newTestDocument(t, filepath.Join(filepath.Dir(root), "cmd", "a.go"), "package a\n")
+
+	off := analysis.RuleSettings{Disabled: true, Severity: diag.SeverityWarning}
+	short := map[string]interface{}{"max": 10}
+	long := map[string]interface{}{"max": 50}
+
+	checkRuleSettings(t, c, main, printlnInfo, off)
+	checkRuleSettings(t, c, main, longFuncInfo, analysis.RuleSettings{Severity: diag.SeverityInfo, Options: short})
+	checkRuleSettings(t, c, main, otherInfo, analysis.RuleSettings{Severity: diag.SeverityWarning})
+
+	checkRuleSettings(t, c, cmd, printlnInfo, analysis.RuleSettings{Severity: diag.SeverityError})
+	checkRuleSettings(t, c, outside, printlnInfo, off)
+
+	checkRuleSettings(t, c, internal, longFuncInfo, analysis.RuleSettings{Severity: diag.SeverityInfo, Options: long})
+	checkRuleSettings(t, c, test, longFuncInfo, analysis.RuleSettings{Severity: diag.SeverityInfo, Options: long})
+
+	checkRuleSettings(t, c, generated, otherInfo, off)
+	checkRuleSettings(t, c, generated, printlnInfo, analysis.RuleSettings{Disabled: true, Severity: diag.SeverityError})
+
+	assert.NoError(t, c.Validate([]analysis.RuleInfo{printlnInfo, longFuncInfo}))
+	err := c.Validate([]analysis.RuleInfo{printlnInfo})
+	if assert.Error(t, err) {
+		assert.Equal(t, c.Path+": rules.long-func: unknown rule", err.Error())
+	}
+}
+
+func TestConfig_JSON(t *testing.T) {
+	c, err := config.Load("testdata/json")
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join("testdata", "json", ".goanalyzer.json"), c.Path)
+		checkConfigSettings(t, c)
+	}
+}
+
+func TestConfig_YAML(t *testing.T) {
+	c, err := config.Load("testdata/yaml")
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join("testdata", "yaml", ".goanalyzer.yaml"), c.Path)
+		checkConfigSettings(t, c)
+	}
+}
+
+func TestConfig_Dir(t *testing.T) {
+	c, err := config.Load("testdata/json")
+	assert.NoError(t, err)
+	doc := newTestDocument(t, filepath.Join("tool", "main.go"), "package main\n")
+	off := analysis.RuleSettings{Disabled: true, Severity: diag.SeverityWarning}
+	checkRuleSettings(t, c, doc, printlnInfo, off)
+
+	c.Dir = filepath.Join("testdata", "json", "cmd")
+	checkRuleSettings(t, c, doc, printlnInfo, analysis.RuleSettings{Severity: diag.SeverityError})
+}
+
+func TestConfig_Default(t *testing.T) {
+	c := config.New(".")
+	doc := newTestDocument(t, "a.go", "package a\n")
+	checkRuleSettings(t, c, doc, printlnInfo, analysis.GetDefaultSettings(printlnInfo))
+	assert.NoError(t, c.Validate(nil))
+}
+
+func TestConfig_Driver(t *testing.T) {
+	c, err := config.Parse(".goanalyzer.json", []byte(`{"rules": {"GA9000": "error"}, "overrides": [{"files": "b.go", "rules": {"GA9000": "off"}}]}`))
+	assert.NoError(t, err)
+
+	driver := analysis.NewDriver(identRule{})
+	driver.Settings = c
+	a := newTestDocument(t, "a.go", "package a\n")
+	b := newTestDocument(t, "b.go", "package b\n")
+
+	messages := []string{}
+	for _, d := range driver.AnalyzeDocuments([]*syntax.Document{a, b}) {
+		messages = append(messages, d.String())
+	}
+	assert.Equal(t, []string{"a.go[8..9): error GA9000: identifier a"}, messages)
+}
+
+func TestIsGenerated(t *testing.T) {
+	assert.True(t, config.IsGenerated(newTestDocument(t, "a.go", generatedSrc)))
+	assert.False(t, config.IsGenerated(newTestDocument(t, "a.go", "package a\n\n// Code generated by tool. DO NOT EDIT.\n")))
+	assert.False(t, config.IsGenerated(newTestDocument(t, "a.go", "// Code generated by tool.\npackage a\n")))
+}
+
+// identRule reports identifiers
+type identRule struct{}
+
+func (r identRule) Metadata() analysis.RuleInfo {
+	return printlnInfo
+}
+
+func (r identRule) Initialize(ctx *analysis.RuleContext) {
+	ctx.RegisterTokenAction(func(ctx *analysis.TokenContext) {
+		ctx.ReportAt(ctx.Token, "identifier %s", ctx.Token.GetText())
+	}, token.IDENT)
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchGlob checks if the slash separated path matches the pattern. Pattern segments are matched
+// with path.Match and ** segment matches any number of path segments.
+func MatchGlob(pattern string, name string) bool {
+	return matchSegments(splitPath(pattern), splitPath(name))
+}
+
+// MatchFiles checks if the pattern matches the path or one of its parent directories
+func MatchFiles(pattern string, name string) bool {
+	patterns := splitPath(pattern)
+	names := splitPath(name)
+	for i := len(names); i > 0; i-- {
+		if matchSegments(patterns, names[:i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateGlob checks the pattern syntax
+func ValidateGlob(pattern string) error {
+	for _, segment := range splitPath(pattern) {
+		if segment == "**" {
+			continue
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if p == "" {
+		return []string{}
+	}
+	return strings.Split(p, "/")
+}
+
+func matchSegments(patterns []string, names []string) bool {
+	if len(patterns) == 0 {
+		return len(names) == 0
+	}
+
+	if patterns[0] == "**" {
+		for i := 0; i <= len(names); i++ {
+			if matchSegments(patterns[1:], names[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(names) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(patterns[0], names[0]); !ok {
+		return false
+	}
+	return matchSegments(patterns[1:], names[1:])
+}
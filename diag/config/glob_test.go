@@ -0,0 +1,38 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGlob(t *testing.T) {
+	assert.True(t, config.MatchGlob("a.go", "a.go"))
+	assert.True(t, config.MatchGlob("*.go", "a.go"))
+	assert.False(t, config.MatchGlob("*.go", "cmd/a.go"))
+	assert.True(t, config.MatchGlob("cmd/*.go", "cmd/a.go"))
+	assert.True(t, config.MatchGlob("**/*.go", "a.go"))
+	assert.True(t, config.MatchGlob("**/*.go", "cmd/x/a.go"))
+	assert.True(t, config.MatchGlob("cmd/**", "cmd/x/a.go"))
+	assert.True(t, config.MatchGlob("cmd/**/a.go", "cmd/a.go"))
+	assert.True(t, config.MatchGlob("cmd/**/a.go", "cmd/x/y/a.go"))
+	assert.False(t, config.MatchGlob("cmd/**/a.go", "internal/a.go"))
+	assert.True(t, config.MatchGlob("./cmd/", "cmd"))
+	assert.False(t, config.MatchGlob("cmd", "cmd/a.go"))
+}
+
+func TestMatchFiles(t *testing.T) {
+	assert.True(t, config.MatchFiles("cmd", "cmd/a.go"))
+	assert.True(t, config.MatchFiles("cmd", "cmd/x/a.go"))
+	assert.False(t, config.MatchFiles("cmd", "internal/cmd/a.go"))
+	assert.True(t, config.MatchFiles("**/cmd", "internal/cmd/a.go"))
+	assert.True(t, config.MatchFiles("**/*_test.go", "x/a_test.go"))
+	assert.False(t, config.MatchFiles("**/*_test.go", "x/a.go"))
+	assert.True(t, config.MatchFiles("*/testdata", "x/testdata/a.go"))
+}
+
+func TestValidateGlob(t *testing.T) {
+	assert.NoError(t, config.ValidateGlob("**/[a-z]*.go"))
+	assert.Error(t, config.ValidateGlob("cmd/[a-"))
+}
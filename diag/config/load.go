@@ -0,0 +1,407 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"gopkg.in/yaml.v3"
+)
+
+// FileNames are names of configuration files which are looked up in the module root
+var FileNames = []string{".goanalyzer.json", ".goanalyzer.yaml", ".goanalyzer.yml"}
+
+// FindModuleRoot returns the closest directory which contains go.mod starting from dir
+func FindModuleRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+			return dir, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// LoadFromModuleRoot loads configuration from the root of the module which contains dir.
+// If dir is not in a module configuration is loaded from dir.
+func LoadFromModuleRoot(dir string) (*Config, error) {
+	if root, ok := FindModuleRoot(dir); ok {
+		return Load(root)
+	}
+	return Load(dir)
+}
+
+// Load loads configuration file from the directory, default configuration is returned if there is no file
+func Load(dir string) (*Config, error) {
+	found := []string{}
+	for _, name := range FileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return New(dir), nil
+	case 1:
+		return LoadFile(found[0])
+	}
+	return nil, fmt.Errorf("%s: several configuration files found: %s", dir, strings.Join(found, ", "))
+}
+
+// LoadFile loads configuration file, the root of the configuration is the file directory
+func LoadFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, data)
+}
+
+// Parse parses configuration file content, the file format is selected by the path extension
+func Parse(path string, data []byte) (*Config, error) {
+	var value interface{}
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		if err := decodeJSON(data, &value); err != nil {
+			return nil, &Error{Path: path, Message: err.Error()}
+		}
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &value); err != nil {
+			return nil, &Error{Path: path, Message: err.Error()}
+		}
+	default:
+		return nil, &Error{Path: path, Message: fmt.Sprintf("unsupported configuration format %q", ext)}
+	}
+
+	c := New(filepath.Dir(path))
+	c.Path = path
+	p := parser{path: path}
+	if err := p.parseConfig(c, value); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// decodeJSON decodes the only JSON value, syntax errors are reported with line and column
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	err := decoder.Decode(v)
+	if err == nil && decoder.More() {
+		err = fmt.Errorf("unexpected data after the top level value")
+	}
+
+	if e, ok := err.(*json.SyntaxError); ok {
+		prefix := data[:e.Offset]
+		line := bytes.Count(prefix, []byte("\n")) + 1
+		column := int(e.Offset) - bytes.LastIndexByte(prefix, '\n') - 1
+		return fmt.Errorf("line %d, column %d: %v", line, column, err)
+	}
+	return err
+}
+
+type parser struct {
+	path string
+}
+
+func (p *parser) errorf(key string, format string, args ...interface{}) error {
+	return &Error{Path: p.path, Key: key, Message: fmt.Sprintf(format, args...)}
+}
+
+// getObject returns object value with sorted keys, keys which are not in known are reported as errors
+func (p *parser) getObject(key string, value interface{}, known ...string) (map[string]interface{}, []string, error) {
+	var object map[string]interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		object = v
+	case map[interface{}]interface{}:
+		var err error
+		if object, err = p.getStringMap(key, v); err != nil {
+			return nil, nil, err
+		}
+	case nil:
+		object = map[string]interface{}{}
+	default:
+		return nil, nil, p.errorf(key, "expected object, got %s", getTypeName(value))
+	}
+
+	keys := []string{}
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if len(known) > 0 {
+		for _, k := range keys {
+			if !contains(known, k) {
+				return nil, nil, p.errorf(joinKey(key, k), "unknown key, expected one of: %s", strings.Join(known, ", "))
+			}
+		}
+	}
+	return object, keys, nil
+}
+
+func (p *parser) parseConfig(c *Config, value interface{}) error {
+	object, _, err := p.getObject("", value, "rules", "overrides")
+	if err != nil {
+		return err
+	}
+
+	if c.Rules, err = p.parseRules("rules", object["rules"]); err != nil {
+		return err
+	}
+
+	overrides, ok := object["overrides"].([]interface{})
+	if !ok && object["overrides"] != nil {
+		return p.errorf("overrides", "expected array, got %s", getTypeName(object["overrides"]))
+	}
+	for i, value := range overrides {
+		o, err := p.parseOverride(fmt.Sprintf("overrides[%d]", i), value)
+		if err != nil {
+			return err
+		}
+		c.Overrides = append(c.Overrides, o)
+	}
+	return nil
+}
+
+func (p *parser) parseOverride(key string, value interface{}) (Override, error) {
+	o := Override{}
+	object, _, err := p.getObject(key, value, "files", "generated", "rules")
+	if err != nil {
+		return o, err
+	}
+
+	switch files := object["files"].(type) {
+	case nil:
+	case string:
+		o.Files = []string{files}
+	case []interface{}:
+		for i, file := range files {
+			pattern, ok := file.(string)
+			if !ok {
+				return o, p.errorf(fmt.Sprintf("%s.files[%d]", key, i), "expected string, got %s", getTypeName(file))
+			}
+			o.Files = append(o.Files, pattern)
+		}
+	default:
+		return o, p.errorf(key+".files", "expected string or array of strings, got %s", getTypeName(files))
+	}
+
+	for i, pattern := range o.Files {
+		if err := ValidateGlob(pattern); err != nil {
+			return o, p.errorf(fmt.Sprintf("%s.files[%d]", key, i), "invalid pattern %q", pattern)
+		}
+	}
+
+	switch generated := object["generated"].(type) {
+	case nil:
+	case bool:
+		o.Generated = generated
+	default:
+		return o, p.errorf(key+".generated", "expected boolean, got %s", getTypeName(generated))
+	}
+
+	if len(o.Files) == 0 && !o.Generated {
+		return o, p.errorf(key, "override must have files or generated: true")
+	}
+
+	o.Rules, err = p.parseRules(key+".rules", object["rules"])
+	return o, err
+}
+
+func (p *parser) parseRules(key string, value interface{}) (map[string]RuleConfig, error) {
+	object, keys, err := p.getObject(key, value)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := map[string]RuleConfig{}
+	for _, name := range keys {
+		rule, err := p.parseRule(joinKey(key, name), object[name])
+		if err != nil {
+			return nil, err
+		}
+		rules[name] = rule
+	}
+	return rules, nil
+}
+
+// parseRule parses rule configuration which is either "off", "on", severity, boolean
+// or object with enabled, severity and options keys
+func (p *parser) parseRule(key string, value interface{}) (RuleConfig, error) {
+	rule := RuleConfig{}
+	switch v := value.(type) {
+	case bool:
+		rule.Enabled = &v
+		return rule, nil
+	case string:
+		return rule, p.parseRuleState(key, v, &rule)
+	}
+
+	if _, ok := value.(map[string]interface{}); !ok {
+		return rule, p.errorf(key, "expected \"off\", \"on\", severity, boolean or object, got %s", getTypeName(value))
+	}
+
+	object, _, err := p.getObject(key, value, "enabled", "severity", "options")
+	if err != nil {
+		return rule, err
+	}
+
+	switch enabled := object["enabled"].(type) {
+	case nil:
+	case bool:
+		rule.Enabled = &enabled
+	default:
+		return rule, p.errorf(key+".enabled", "expected boolean, got %s", getTypeName(enabled))
+	}
+
+	switch severity := object["severity"].(type) {
+	case nil:
+	case string:
+		s, ok := diag.ParseSeverity(severity)
+		if !ok {
+			return rule, p.errorf(key+".severity", "invalid severity %q, expected hidden, info, warning or error", severity)
+		}
+		rule.Severity = &s
+	default:
+		return rule, p.errorf(key+".severity", "expected string, got %s", getTypeName(severity))
+	}
+
+	if object["options"] != nil {
+		options, err := p.normalize(key+".options", object["options"])
+		if err != nil {
+			return rule, err
+		}
+		var ok bool
+		if rule.Options, ok = options.(map[string]interface{}); !ok {
+			return rule, p.errorf(key+".options", "expected object, got %s", getTypeName(options))
+		}
+	}
+	return rule, nil
+}
+
+func (p *parser) parseRuleState(key string, state string, rule *RuleConfig) error {
+	enabled := true
+	switch strings.ToLower(state) {
+	case "off":
+		enabled = false
+	case "on":
+	default:
+		s, ok := diag.ParseSeverity(state)
+		if !ok {
+			return p.errorf(key, "invalid value %q, expected \"off\", \"on\" or severity", state)
+		}
+		rule.Severity = &s
+	}
+	rule.Enabled = &enabled
+	return nil
+}
+
+// normalize converts YAML mappings with non-string keys to objects, so options can be encoded as JSON,
+// and integral JSON numbers to int, so options have the same types in both formats
+func (p *parser) normalize(key string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+			return int(v), nil
+		}
+		return v, nil
+	case map[string]interface{}:
+		object := map[string]interface{}{}
+		for k, item := range v {
+			item, err := p.normalize(joinKey(key, k), item)
+			if err != nil {
+				return nil, err
+			}
+			object[k] = item
+		}
+		return object, nil
+	case map[interface{}]interface{}:
+		object, err := p.getStringMap(key, v)
+		if err != nil {
+			return nil, err
+		}
+		return p.normalize(key, object)
+	case []interface{}:
+		array := []interface{}{}
+		for i, item := range v {
+			item, err := p.normalize(fmt.Sprintf("%s[%d]", key, i), item)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, item)
+		}
+		return array, nil
+	}
+	return value, nil
+}
+
+// getStringMap converts YAML mapping to object, the first of the keys which are not strings is reported as error
+func (p *parser) getStringMap(key string, m map[interface{}]interface{}) (map[string]interface{}, error) {
+	object := map[string]interface{}{}
+	invalid := []string{}
+	for k, item := range m {
+		if name, ok := k.(string); ok {
+			object[name] = item
+		} else {
+			invalid = append(invalid, fmt.Sprint(k))
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return nil, p.errorf(key, "key %s is not a string", invalid[0])
+	}
+	return object, nil
+}
+
+func joinKey(key string, name string) string {
+	if key == "" {
+		return name
+	}
+	return key + "." + name
+}
+
+func getTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int, int64, uint64, float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
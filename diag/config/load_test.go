@@ -0,0 +1,89 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkParseError(t *testing.T, path string, data string, expected string) {
+	t.Helper()
+	_, err := config.Parse(path, []byte(data))
+	if assert.Error(t, err) {
+		assert.Equal(t, expected, err.Error())
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	checkParseError(t, "c.json", `{"rulez": {}}`, `c.json: rulez: unknown key, expected one of: rules, overrides`)
+	checkParseError(t, "c.json", `[]`, `c.json: expected object, got array`)
+	checkParseError(t, "c.json", "{\n  \"rules\": {,}\n}", `c.json: line 2, column 13: invalid character ',' looking for beginning of object key string`)
+	checkParseError(t, "c.json", `{} {}`, `c.json: unexpected data after the top level value`)
+	checkParseError(t, "c.json", `{"rules": {"GA1": "warn"}}`, `c.json: rules.GA1: invalid value "warn", expected "off", "on" or severity`)
+	checkParseError(t, "c.json", `{"rules": {"GA1": 1}}`, `c.json: rules.GA1: expected "off", "on", severity, boolean or object, got number`)
+	checkParseError(t, "c.json", `{"rules": {"GA1": {"level": "error"}}}`, `c.json: rules.GA1.level: unknown key, expected one of: enabled, severity, options`)
+	checkParseError(t, "c.json", `{"rules": {"GA1": {"severity": "fatal"}}}`, `c.json: rules.GA1.severity: invalid severity "fatal", expected hidden, info, warning or error`)
+	checkParseError(t, "c.json", `{"rules": {"GA1": {"enabled": "yes"}}}`, `c.json: rules.GA1.enabled: expected boolean, got string`)
+	checkParseError(t, "c.json", `{"rules": {"GA1": {"options": []}}}`, `c.json: rules.GA1.options: expected object, got array`)
+	checkParseError(t, "c.json", `{"overrides": {}}`, `c.json: overrides: expected array, got object`)
+	checkParseError(t, "c.json", `{"overrides": [{"rules": {}}]}`, `c.json: overrides[0]: override must have files or generated: true`)
+	checkParseError(t, "c.json", `{"overrides": [{"files": [1]}]}`, `c.json: overrides[0].files[0]: expected string, got number`)
+	checkParseError(t, "c.json", `{"overrides": [{"files": ["[a-"]}]}`, `c.json: overrides[0].files[0]: invalid pattern "[a-"`)
+	checkParseError(t, "c.json", `{"overrides": [{"files": "a", "rules": {"GA1": "x"}}]}`, `c.json: overrides[0].rules.GA1: invalid value "x", expected "off", "on" or severity`)
+	checkParseError(t, "c.yaml", "rules:\n  GA1:\n    options:\n      1: x\n", `c.yaml: rules.GA1.options: key 1 is not a string`)
+	checkParseError(t, "c.yaml", "rules:\n  1: off\n  2: on\n", `c.yaml: rules: key 1 is not a string`)
+	checkParseError(t, "c.yaml", "1: x\n", `c.yaml: key 1 is not a string`)
+	checkParseError(t, "c.yaml", "rules: [", `c.yaml: yaml: line 1: did not find expected node content`)
+	checkParseError(t, "c.toml", "", `c.toml: unsupported configuration format ".toml"`)
+}
+
+func TestParse_YAMLOptions(t *testing.T) {
+	c, err := config.Parse("c.yml", []byte("rules:\n  GA1:\n    options:\n      names: [a, b]\n      nested: {x: 1}\n"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]interface{}{
+			"names":  []interface{}{"a", "b"},
+			"nested": map[string]interface{}{"x": 1},
+		}, c.Rules["GA1"].Options)
+		assert.Nil(t, c.Rules["GA1"].Enabled)
+		assert.Nil(t, c.Rules["GA1"].Severity)
+	}
+}
+
+func TestLoadFromModuleRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	sub := filepath.Join(dir, "cmd", "tool")
+	assert.NoError(t, os.MkdirAll(sub, 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module m\n"), 0644))
+
+	root, ok := config.FindModuleRoot(sub)
+	assert.True(t, ok)
+	assert.Equal(t, dir, root)
+
+	c, err := config.LoadFromModuleRoot(sub)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "", c.Path)
+		assert.Equal(t, dir, c.Root)
+	}
+
+	yamlPath := filepath.Join(dir, ".goanalyzer.yaml")
+	assert.NoError(t, ioutil.WriteFile(yamlPath, []byte("rules:\n  GA1: off\n"), 0644))
+	c, err = config.LoadFromModuleRoot(sub)
+	if assert.NoError(t, err) {
+		assert.Equal(t, yamlPath, c.Path)
+		assert.Equal(t, dir, c.Root)
+		assert.False(t, *c.Rules["GA1"].Enabled)
+	}
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, ".goanalyzer.json"), []byte("{}"), 0644))
+	_, err = config.LoadFromModuleRoot(sub)
+	assert.Error(t, err)
+}
@@ -0,0 +1,13 @@
+package diag
+
+// Descriptor describes the kind of diagnostics, e.g. the rule which reports them
+type Descriptor struct {
+	ID   string
+	Name string
+	// Title is the short description of the problem
+	Title string
+	// Help is the full description of the problem and how to fix it
+	Help            string
+	HelpURI         string
+	DefaultSeverity Severity
+}
@@ -1 +1,166 @@
 package diag
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// Severity represents severity of the diagnostic
+type Severity int
+
+// Diagnostic severities
+const (
+	SeverityHidden Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+// String returns string representation of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityHidden:
+		return "hidden"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "none"
+}
+
+// ParseSeverity returns severity by its string representation
+func ParseSeverity(str string) (Severity, bool) {
+	for s := SeverityHidden; s <= SeverityError; s++ {
+		if strings.EqualFold(str, s.String()) {
+			return s, true
+		}
+	}
+	return SeverityHidden, false
+}
+
+// Tag represents additional information about the diagnostic which tools can use to present it
+type Tag int
+
+// Diagnostic tags
+const (
+	// TagUnnecessary marks unused or unnecessary code, e.g. editors may render it faded out
+	TagUnnecessary Tag = iota + 1
+	// TagDeprecated marks use of deprecated code, e.g. editors may render it struck through
+	TagDeprecated
+)
+
+// String returns string representation of the tag
+func (t Tag) String() string {
+	switch t {
+	case TagUnnecessary:
+		return "unnecessary"
+	case TagDeprecated:
+		return "deprecated"
+	}
+	return "none"
+}
+
+// Location represents span in the document with the given path
+type Location struct {
+	Path string
+	Span text.TextSpan
+	// Message describes additional location, e.g. "previous declaration is here"
+	Message string
+}
+
+// NewLocation creates new location
+func NewLocation(path string, span text.TextSpan) Location {
+	return Location{Path: path, Span: span}
+}
+
+// NewLabeledLocation creates new location with the message
+func NewLabeledLocation(path string, span text.TextSpan, message string) Location {
+	return Location{Path: path, Span: span, Message: message}
+}
+
+// String returns string representation of the location
+func (l Location) String() string {
+	return fmt.Sprintf("%s%v", l.Path, l.Span)
+}
+
+// CompareTo compares locations by path and then by span
+func (l Location) CompareTo(location Location) int {
+	if r := strings.Compare(l.Path, location.Path); r != 0 {
+		return r
+	}
+	return l.Span.CompareTo(location.Span)
+}
+
+// Diagnostic represents problem found in the source code
+type Diagnostic struct {
+	// ID identifies the kind of the problem, e.g. rule id
+	ID       string
+	Severity Severity
+	// Message is the message format, it is formatted with Args when there are any
+	Message  string
+	Args     []interface{}
+	Location Location
+	// AdditionalLocations are related locations, e.g. previous declaration of a duplicate
+	AdditionalLocations []Location
+	Tags                []Tag
+	// Fixes are suggested code fixes of the problem
+	Fixes []CodeFix
+	// Suppressions are suppressions which apply to the diagnostic, suppressed diagnostics are kept
+	// so tools can report them as suppressed
+	Suppressions []Suppression
+}
+
+// NewDiagnostic creates new diagnostic with the message formatted with args
+func NewDiagnostic(id string, severity Severity, location Location, message string, args ...interface{}) Diagnostic {
+	return Diagnostic{
+		ID:       id,
+		Severity: severity,
+		Message:  message,
+		Args:     args,
+		Location: location,
+	}
+}
+
+// GetMessage returns formatted message
+func (d Diagnostic) GetMessage() string {
+	if len(d.Args) == 0 {
+		return d.Message
+	}
+	return fmt.Sprintf(d.Message, d.Args...)
+}
+
+// IsSuppressed checks if the diagnostic is suppressed
+func (d Diagnostic) IsSuppressed() bool {
+	return len(d.Suppressions) > 0
+}
+
+// HasTag checks if the diagnostic has the tag
+func (d Diagnostic) HasTag(tag Tag) bool {
+	for _, t := range d.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns string representation of the diagnostic
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%v: %v %s: %s", d.Location, d.Severity, d.ID, d.GetMessage())
+}
+
+// CompareTo compares diagnostics by location, then by id and message
+func (d Diagnostic) CompareTo(diagnostic Diagnostic) int {
+	if r := d.Location.CompareTo(diagnostic.Location); r != 0 {
+		return r
+	}
+	if r := strings.Compare(d.ID, diagnostic.ID); r != 0 {
+		return r
+	}
+	return strings.Compare(d.GetMessage(), diagnostic.GetMessage())
+}
@@ -1 +1,75 @@
 package diag_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Severity_String(t *testing.T) {
+	assert.Equal(t, "hidden", diag.SeverityHidden.String())
+	assert.Equal(t, "info", diag.SeverityInfo.String())
+	assert.Equal(t, "warning", diag.SeverityWarning.String())
+	assert.Equal(t, "error", diag.SeverityError.String())
+	assert.Equal(t, "none", diag.Severity(10).String())
+}
+
+func Test_Severity_ParseSeverity(t *testing.T) {
+	s, ok := diag.ParseSeverity("Warning")
+	assert.True(t, ok)
+	assert.Equal(t, diag.SeverityWarning, s)
+
+	_, ok = diag.ParseSeverity("fatal")
+	assert.False(t, ok)
+}
+
+func Test_Tag_String(t *testing.T) {
+	assert.Equal(t, "unnecessary", diag.TagUnnecessary.String())
+	assert.Equal(t, "deprecated", diag.TagDeprecated.String())
+}
+
+func Test_Location_CompareTo(t *testing.T) {
+	l1 := diag.NewLocation("a.go", text.NewTextSpan(10, 2))
+	l2 := diag.NewLocation("a.go", text.NewTextSpan(12, 2))
+	l3 := diag.NewLocation("b.go", text.NewTextSpan(0, 2))
+
+	assert.Equal(t, "a.go[10..12)", l1.String())
+	assert.True(t, l1.CompareTo(l2) < 0)
+	assert.True(t, l3.CompareTo(l2) > 0)
+	assert.Equal(t, 0, l1.CompareTo(l1))
+}
+
+func Test_Diagnostic_GetMessage(t *testing.T) {
+	location := diag.NewLocation("a.go", text.NewTextSpan(1, 3))
+	d := diag.NewDiagnostic("GA1000", diag.SeverityWarning, location, "variable %s is unused", "x")
+	assert.Equal(t, "variable x is unused", d.GetMessage())
+	assert.Equal(t, "a.go[1..4): warning GA1000: variable x is unused", d.String())
+
+	d = diag.NewDiagnostic("GA1000", diag.SeverityWarning, location, "100% unused")
+	assert.Equal(t, "100% unused", d.GetMessage())
+}
+
+func Test_Diagnostic_HasTag(t *testing.T) {
+	d := diag.Diagnostic{Tags: []diag.Tag{diag.TagDeprecated}}
+	assert.True(t, d.HasTag(diag.TagDeprecated))
+	assert.False(t, d.HasTag(diag.TagUnnecessary))
+}
+
+func Test_Diagnostic_IsSuppressed(t *testing.T) {
+	d := diag.Diagnostic{}
+	assert.False(t, d.IsSuppressed())
+
+	d.Suppressions = []diag.Suppression{{Kind: diag.SuppressionExternal}}
+	assert.True(t, d.IsSuppressed())
+	assert.Equal(t, "external", d.Suppressions[0].Kind.String())
+	assert.Equal(t, "inSource", diag.SuppressionInSource.String())
+}
+
+func Test_CodeFix_NewCodeFix(t *testing.T) {
+	c := text.NewTextChange(text.NewTextSpan(1, 2), "x")
+	fix := diag.NewCodeFix("Rename", c)
+	assert.Equal(t, "Rename", fix.Title)
+	assert.Equal(t, []text.TextChange{c}, fix.Changes)
+}
@@ -0,0 +1,16 @@
+package diag
+
+import (
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// CodeFix represents suggested fix of the problem. Changes apply to the document of the diagnostic.
+type CodeFix struct {
+	Title   string
+	Changes []text.TextChange
+}
+
+// NewCodeFix creates new code fix
+func NewCodeFix(title string, changes ...text.TextChange) CodeFix {
+	return CodeFix{Title: title, Changes: changes}
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// CheckstyleRenderer writes diagnostics as Checkstyle XML report
+type CheckstyleRenderer struct {
+	w       io.Writer
+	Sources SourceResolver
+	// SourcePrefix is prepended to diagnostic ids in the source attribute
+	SourcePrefix string
+}
+
+// NewCheckstyleRenderer creates new Checkstyle renderer
+func NewCheckstyleRenderer(w io.Writer, sources SourceResolver) *CheckstyleRenderer {
+	return &CheckstyleRenderer{
+		w:            w,
+		Sources:      sources,
+		SourcePrefix: "goanalyzer.",
+	}
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Render writes Checkstyle report, diagnostics are grouped by file and suppressed diagnostics are skipped
+func (r *CheckstyleRenderer) Render(diags []diag.Diagnostic) error {
+	files := map[string]*checkstyleFile{}
+	for _, d := range GetUnsuppressed(diags) {
+		path := d.Location.Path
+		file, ok := files[path]
+		if !ok {
+			file = &checkstyleFile{Name: path}
+			files[path] = file
+		}
+
+		e := checkstyleError{
+			Severity: getCheckstyleSeverity(d.Severity),
+			Message:  d.GetMessage(),
+			Source:   r.SourcePrefix + d.ID,
+		}
+		if _, span, ok := getLineSpan(r.Sources, d.Location, text.CharacterUnitByte); ok {
+			e.Line = span.Start.Line + 1
+			e.Column = span.Start.Character + 1
+		}
+		file.Errors = append(file.Errors, e)
+	}
+
+	report := checkstyleReport{Version: "8.0", Files: []checkstyleFile{}}
+	for _, file := range files {
+		report.Files = append(report.Files, *file)
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Name < report.Files[j].Name
+	})
+	return writeXML(r.w, report)
+}
+
+func getCheckstyleSeverity(severity diag.Severity) string {
+	switch severity {
+	case diag.SeverityError:
+		return "error"
+	case diag.SeverityWarning:
+		return "warning"
+	case diag.SeverityInfo:
+		return "info"
+	}
+	return "ignore"
+}
+
+func writeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
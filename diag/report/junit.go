@@ -0,0 +1,143 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// JUnitRenderer writes diagnostics as JUnit XML report. Each file is a test suite and each rule
+// which reported diagnostics in the file is a failed test case.
+type JUnitRenderer struct {
+	w       io.Writer
+	Sources SourceResolver
+	// Name is the name of the test suites
+	Name string
+}
+
+// NewJUnitRenderer creates new JUnit renderer
+func NewJUnitRenderer(w io.Writer, sources SourceResolver) *JUnitRenderer {
+	return &JUnitRenderer{
+		w:       w,
+		Sources: sources,
+		Name:    "goanalyzer",
+	}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// Render writes JUnit report, suppressed diagnostics are skipped. If there are no diagnostics a single passed test case is written,
+// so CI tools do not treat the report as empty.
+func (r *JUnitRenderer) Render(diags []diag.Diagnostic) error {
+	files := map[string]map[string][]diag.Diagnostic{}
+	for _, d := range GetUnsuppressed(diags) {
+		rules, ok := files[d.Location.Path]
+		if !ok {
+			rules = map[string][]diag.Diagnostic{}
+			files[d.Location.Path] = rules
+		}
+		rules[d.ID] = append(rules[d.ID], d)
+	}
+
+	report := junitTestSuites{Name: r.Name}
+	for _, path := range getSortedKeys(files) {
+		rules := files[path]
+		suite := junitTestSuite{Name: path}
+		ids := []string{}
+		for id := range rules {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      id,
+				ClassName: path,
+				Failure:   r.getFailure(rules[id]),
+			})
+		}
+		suite.Tests = len(suite.Cases)
+		suite.Failures = len(suite.Cases)
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if len(report.Suites) == 0 {
+		report.Suites = append(report.Suites, junitTestSuite{
+			Name:  r.Name,
+			Tests: 1,
+			Cases: []junitTestCase{{Name: r.Name, ClassName: r.Name}},
+		})
+	}
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+	}
+	return writeXML(r.w, report)
+}
+
+func (r *JUnitRenderer) getFailure(diags []diag.Diagnostic) *junitFailure {
+	severity := diag.SeverityHidden
+	lines := []string{}
+	for _, d := range diags {
+		if d.Severity > severity {
+			severity = d.Severity
+		}
+
+		path := d.Location.Path
+		if _, span, ok := getLineSpan(r.Sources, d.Location, text.CharacterUnitByte); ok {
+			path = formatLinePosition(path, span.Start)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %v %s: %s", path, d.Severity, d.ID, d.GetMessage()))
+	}
+
+	message := diags[0].GetMessage()
+	if len(diags) > 1 {
+		message = fmt.Sprintf("%d problems", len(diags))
+	}
+
+	return &junitFailure{
+		Message: message,
+		Type:    severity.String(),
+		Text:    strings.Join(lines, "\n"),
+	}
+}
+
+func getSortedKeys(m map[string]map[string][]diag.Diagnostic) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// RdjsonRenderer writes diagnostics in reviewdog diagnostic format (rdjson).
+// Changes of the first code fix of the diagnostic are written as suggestions if the source is available.
+type RdjsonRenderer struct {
+	w           io.Writer
+	Sources     SourceResolver
+	Descriptors []diag.Descriptor
+	ToolName    string
+	ToolURL     string
+}
+
+// NewRdjsonRenderer creates new rdjson renderer
+func NewRdjsonRenderer(w io.Writer, sources SourceResolver, descriptors []diag.Descriptor) *RdjsonRenderer {
+	return &RdjsonRenderer{
+		w:           w,
+		Sources:     sources,
+		Descriptors: descriptors,
+		ToolName:    "goanalyzer",
+	}
+}
+
+type rdjsonResult struct {
+	Source      rdjsonSource       `json:"source"`
+	Diagnostics []rdjsonDiagnostic `json:"diagnostics"`
+}
+
+type rdjsonSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdjsonDiagnostic struct {
+	Message          string                  `json:"message"`
+	Location         rdjsonLocation          `json:"location"`
+	Severity         string                  `json:"severity"`
+	Code             rdjsonCode              `json:"code"`
+	Suggestions      []rdjsonSuggestion      `json:"suggestions,omitempty"`
+	RelatedLocations []rdjsonRelatedLocation `json:"related_locations,omitempty"`
+}
+
+type rdjsonLocation struct {
+	Path  string       `json:"path"`
+	Range *rdjsonRange `json:"range,omitempty"`
+}
+
+type rdjsonRange struct {
+	Start rdjsonPosition `json:"start"`
+	End   rdjsonPosition `json:"end"`
+}
+
+type rdjsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type rdjsonCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type rdjsonSuggestion struct {
+	Range *rdjsonRange `json:"range"`
+	Text  string       `json:"text"`
+}
+
+type rdjsonRelatedLocation struct {
+	Message  string         `json:"message,omitempty"`
+	Location rdjsonLocation `json:"location"`
+}
+
+// Render writes rdjson diagnostic result, suppressed diagnostics are skipped
+func (r *RdjsonRenderer) Render(diags []diag.Diagnostic) error {
+	urls := map[string]string{}
+	for _, d := range r.Descriptors {
+		urls[d.ID] = d.HelpURI
+	}
+
+	result := rdjsonResult{
+		Source:      rdjsonSource{Name: r.ToolName, URL: r.ToolURL},
+		Diagnostics: []rdjsonDiagnostic{},
+	}
+	for _, d := range GetUnsuppressed(diags) {
+		rd := rdjsonDiagnostic{
+			Message:  d.GetMessage(),
+			Location: r.getLocation(d.Location.Path, d.Location.Span),
+			Severity: getRdjsonSeverity(d.Severity),
+			Code:     rdjsonCode{Value: d.ID, URL: urls[d.ID]},
+		}
+
+		if len(d.Fixes) > 0 {
+			for _, c := range d.Fixes[0].Changes {
+				// suggestions can not be written without the source range
+				if rng := r.getLocation(d.Location.Path, c.Span).Range; rng != nil {
+					rd.Suggestions = append(rd.Suggestions, rdjsonSuggestion{Range: rng, Text: c.NewText})
+				}
+			}
+		}
+
+		for _, loc := range d.AdditionalLocations {
+			rd.RelatedLocations = append(rd.RelatedLocations, rdjsonRelatedLocation{
+				Message:  loc.Message,
+				Location: r.getLocation(loc.Path, loc.Span),
+			})
+		}
+		result.Diagnostics = append(result.Diagnostics, rd)
+	}
+
+	encoder := json.NewEncoder(r.w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(result)
+}
+
+// getLocation returns location of the span, columns are 1-based byte offsets and the range end is exclusive
+func (r *RdjsonRenderer) getLocation(path string, span text.TextSpan) rdjsonLocation {
+	loc := rdjsonLocation{Path: path}
+	if _, lineSpan, ok := getLineSpan(r.Sources, diag.NewLocation(path, span), text.CharacterUnitByte); ok {
+		loc.Range = &rdjsonRange{
+			Start: rdjsonPosition{Line: lineSpan.Start.Line + 1, Column: lineSpan.Start.Character + 1},
+			End:   rdjsonPosition{Line: lineSpan.End.Line + 1, Column: lineSpan.End.Character + 1},
+		}
+	}
+	return loc
+}
+
+func getRdjsonSeverity(severity diag.Severity) string {
+	switch severity {
+	case diag.SeverityError:
+		return "ERROR"
+	case diag.SeverityWarning:
+		return "WARNING"
+	case diag.SeverityInfo:
+		return "INFO"
+	}
+	return "UNKNOWN_SEVERITY"
+}
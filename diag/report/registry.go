@@ -0,0 +1,94 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/a6cexz/goanalyzer/diag"
+)
+
+// Renderer writes diagnostics in some format
+type Renderer interface {
+	Render(diags []diag.Diagnostic) error
+}
+
+// Options are options of the renderer created by format name
+type Options struct {
+	Sources SourceResolver
+	// Descriptors are metadata of rules which reported diagnostics
+	Descriptors []diag.Descriptor
+	// NoColor disables colors even if the output is a terminal
+	NoColor bool
+}
+
+// Factory creates renderer which writes to w
+type Factory func(w io.Writer, options Options) Renderer
+
+// Names of the built-in formats
+const (
+	FormatText       = "text"
+	FormatSarif      = "sarif"
+	FormatCheckstyle = "checkstyle"
+	FormatJUnit      = "junit"
+	FormatRdjson     = "rdjson"
+)
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Factory{}
+)
+
+func init() {
+	Register(FormatText, func(w io.Writer, options Options) Renderer {
+		r := NewTextRenderer(w, options.Sources)
+		r.Color = r.Color && !options.NoColor
+		return r
+	})
+	Register(FormatSarif, func(w io.Writer, options Options) Renderer {
+		return NewSarifRenderer(w, options.Sources, options.Descriptors)
+	})
+	Register(FormatCheckstyle, func(w io.Writer, options Options) Renderer {
+		return NewCheckstyleRenderer(w, options.Sources)
+	})
+	Register(FormatJUnit, func(w io.Writer, options Options) Renderer {
+		return NewJUnitRenderer(w, options.Sources)
+	})
+	Register(FormatRdjson, func(w io.Writer, options Options) Renderer {
+		return NewRdjsonRenderer(w, options.Sources, options.Descriptors)
+	})
+}
+
+// Register registers format with the given name, it panics if the format is already registered
+func Register(name string, factory Factory) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if _, ok := formats[name]; ok {
+		panic(fmt.Sprintf("format %s is already registered!", name))
+	}
+	formats[name] = factory
+}
+
+// NewRenderer creates renderer of the format with the given name
+func NewRenderer(name string, w io.Writer, options Options) (Renderer, error) {
+	formatsMu.RLock()
+	factory, ok := formats[name]
+	formatsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q, available formats: %v", name, GetFormats())
+	}
+	return factory(w, options), nil
+}
+
+// GetFormats returns sorted names of the registered formats
+func GetFormats() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	names := []string{}
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,69 @@
+package report_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/report"
+	"github.com/stretchr/testify/assert"
+)
+
+type countRenderer struct {
+	w io.Writer
+}
+
+func (r countRenderer) Render(diags []diag.Diagnostic) error {
+	_, err := io.WriteString(r.w, string(rune('0'+len(diags))))
+	return err
+}
+
+func TestGetFormats(t *testing.T) {
+	formats := report.GetFormats()
+	for _, name := range []string{"checkstyle", "junit", "rdjson", "sarif", "text"} {
+		assert.Contains(t, formats, name)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	// formats are global, the format is already registered if the test is run several times
+	if !contains(report.GetFormats(), "count") {
+		report.Register("count", func(w io.Writer, options report.Options) report.Renderer {
+			return countRenderer{w: w}
+		})
+	}
+	assert.Equal(t, "4", renderFormat(t, "count", getTestDiagnostics()))
+
+	assert.Panics(t, func() {
+		report.Register("count", func(w io.Writer, options report.Options) report.Renderer {
+			return countRenderer{w: w}
+		})
+	})
+}
+
+func TestNewRendererUnknownFormat(t *testing.T) {
+	_, err := report.NewRenderer("xml", &bytes.Buffer{}, report.Options{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown format "xml"`)
+}
+
+func TestNewRendererText(t *testing.T) {
+	diags := getTestDiagnostics()[3:]
+	expected := "" +
+		"b.go:3:5: warning GA1002: z is unused\n" +
+		"3 | var z int\n" +
+		"  |     ^\n" +
+		"\n" +
+		"1 warning\n"
+	assert.Equal(t, expected, renderFormat(t, report.FormatText, diags))
+}
+
+func contains(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
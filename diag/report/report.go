@@ -0,0 +1,78 @@
+package report
+
+import (
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// SourceResolver resolves source text of the document by its path
+type SourceResolver interface {
+	GetSourceText(path string) (*text.SourceText, bool)
+}
+
+// SourceMap is the SourceResolver which maps paths to source texts
+type SourceMap map[string]*text.SourceText
+
+// GetSourceText returns source text with the given path
+func (m SourceMap) GetSourceText(path string) (*text.SourceText, bool) {
+	src, ok := m[path]
+	return src, ok
+}
+
+// Summary represents number of diagnostics of each severity
+type Summary struct {
+	Errors   int
+	Warnings int
+	Infos    int
+	Hidden   int
+	// Suppressed diagnostics are not counted in severities
+	Suppressed int
+}
+
+// GetSummary counts diagnostics of each severity
+func GetSummary(diags []diag.Diagnostic) Summary {
+	s := Summary{}
+	for _, d := range diags {
+		if d.IsSuppressed() {
+			s.Suppressed++
+			continue
+		}
+
+		switch d.Severity {
+		case diag.SeverityError:
+			s.Errors++
+		case diag.SeverityWarning:
+			s.Warnings++
+		case diag.SeverityInfo:
+			s.Infos++
+		default:
+			s.Hidden++
+		}
+	}
+	return s
+}
+
+// GetUnsuppressed returns diagnostics which are not suppressed
+func GetUnsuppressed(diags []diag.Diagnostic) []diag.Diagnostic {
+	result := []diag.Diagnostic{}
+	for _, d := range diags {
+		if !d.IsSuppressed() {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// getLineSpan returns line position span of the location, ok is false if the source is not available
+// or the span is out of the source range
+func getLineSpan(sources SourceResolver, loc diag.Location, unit text.CharacterUnit) (*text.SourceText, text.LinePositionSpan, bool) {
+	if sources == nil {
+		return nil, text.LinePositionSpan{}, false
+	}
+
+	src, ok := sources.GetSourceText(loc.Path)
+	if !ok || src == nil || !loc.Span.IsValid() || loc.Span.End() > src.Length() {
+		return nil, text.LinePositionSpan{}, false
+	}
+	return src, src.GetLinePositionSpan(loc.Span, unit), true
+}
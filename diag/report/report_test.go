@@ -0,0 +1,145 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/report"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ report.SourceResolver = syntax.NewDocumentRegistry()
+
+func getTestDiagnostics() []diag.Diagnostic {
+	d1 := diag.NewDiagnostic("GA1000", diag.SeverityError, diag.NewLocation("a.go", text.NewTextSpan(42, 8)), "mismatched <types> & values")
+	d1.Fixes = []diag.CodeFix{
+		diag.NewCodeFix("Use y as string", text.NewTextChange(text.NewTextSpan(53, 1), "string(y)")),
+	}
+
+	d2 := diag.NewDiagnostic("GA1001", diag.SeverityWarning, diag.NewLocation("a.go", text.NewTextSpan(38, 1)), "%s shadows declaration", "x")
+	d2.AdditionalLocations = []diag.Location{diag.NewLabeledLocation("a.go", text.NewTextSpan(15, 1), "declared here")}
+
+	d3 := diag.NewDiagnostic("GA1001", diag.SeverityInfo, diag.NewLocation("a.go", text.NewTextSpan(15, 1)), "x is shadowed")
+	d4 := diag.NewDiagnostic("GA1002", diag.SeverityWarning, diag.NewLocation("b.go", text.NewTextSpan(15, 1)), "z is unused")
+	return []diag.Diagnostic{d1, d2, d3, d4}
+}
+
+func renderFormat(t *testing.T, format string, diags []diag.Diagnostic) string {
+	var buffer bytes.Buffer
+	options := report.Options{
+		Sources: newTestSources(),
+		Descriptors: []diag.Descriptor{
+			{ID: "GA1000", HelpURI: "https://example.com/GA1000", DefaultSeverity: diag.SeverityError},
+		},
+		NoColor: true,
+	}
+	r, err := report.NewRenderer(format, &buffer, options)
+	assert.NoError(t, err)
+	assert.NoError(t, r.Render(diags))
+	return buffer.String()
+}
+
+// getNoSourceDiagnostics returns diagnostic with code fix and related location in the file without source
+func getNoSourceDiagnostics() []diag.Diagnostic {
+	d := diag.NewDiagnostic("GA1000", diag.SeverityError, diag.NewLocation("c.go", text.NewTextSpan(1, 2)), "no source")
+	d.Fixes = []diag.CodeFix{diag.NewCodeFix("Fix", text.NewTextChange(text.NewTextSpan(1, 2), "x"))}
+	d.AdditionalLocations = []diag.Location{diag.NewLabeledLocation("c.go", text.NewTextSpan(0, 1), "declared here")}
+	return []diag.Diagnostic{d}
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		format   string
+		golden   string
+		empty    string
+		noSource func(t *testing.T, actual string)
+	}{
+		{
+			format: report.FormatCheckstyle,
+			golden: "testdata/checkstyle.golden",
+			empty:  "testdata/checkstyle_empty.golden",
+			noSource: func(t *testing.T, actual string) {
+				assert.Contains(t, actual, `<error severity="error" message="no source" source="goanalyzer.GA1000"></error>`)
+				assert.NotContains(t, actual, "declared here")
+			},
+		},
+		{
+			format: report.FormatJUnit,
+			golden: "testdata/junit.golden",
+			empty:  "testdata/junit_empty.golden",
+			noSource: func(t *testing.T, actual string) {
+				assert.Contains(t, actual, `<failure message="no source" type="error">c.go: error GA1000: no source</failure>`)
+				assert.NotContains(t, actual, "declared here")
+			},
+		},
+		{
+			format: report.FormatRdjson,
+			golden: "testdata/rdjson.golden",
+			noSource: func(t *testing.T, actual string) {
+				var result map[string]interface{}
+				assert.NoError(t, json.Unmarshal([]byte(actual), &result))
+				d := result["diagnostics"].([]interface{})[0].(map[string]interface{})
+				assert.Equal(t, map[string]interface{}{"path": "c.go"}, d["location"])
+				assert.NotContains(t, d, "suggestions")
+				assert.Equal(t, []interface{}{
+					map[string]interface{}{"message": "declared here", "location": map[string]interface{}{"path": "c.go"}},
+				}, d["related_locations"])
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			asttest.CheckGolden(t, test.golden, renderFormat(t, test.format, getTestDiagnostics()))
+			if test.empty != "" {
+				asttest.CheckGolden(t, test.empty, renderFormat(t, test.format, nil))
+			}
+			test.noSource(t, renderFormat(t, test.format, getNoSourceDiagnostics()))
+		})
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	diags := []diag.Diagnostic{
+		{Severity: diag.SeverityError},
+		{Severity: diag.SeverityWarning},
+		{Severity: diag.SeverityError},
+		{Severity: diag.SeverityHidden},
+		{Severity: diag.SeverityError, Suppressions: []diag.Suppression{{Kind: diag.SuppressionInSource}}},
+	}
+	assert.Equal(t, report.Summary{Errors: 2, Warnings: 1, Hidden: 1, Suppressed: 1}, report.GetSummary(diags))
+}
+
+func TestSuppressedDiagnostics(t *testing.T) {
+	suppressed := diag.NewDiagnostic("GA1003", diag.SeverityError, diag.NewLocation("c.go", text.NewTextSpan(0, 1)), "suppressed")
+	suppressed.Suppressions = []diag.Suppression{{Kind: diag.SuppressionInSource, Justification: "intended"}}
+	diags := getTestDiagnostics()
+	assert.Equal(t, diags, report.GetUnsuppressed(append(diags, suppressed)))
+
+	for _, format := range []string{report.FormatCheckstyle, report.FormatJUnit, report.FormatRdjson} {
+		assert.Equal(t, renderFormat(t, format, diags), renderFormat(t, format, append(diags, suppressed)), format)
+	}
+
+	text := renderFormat(t, report.FormatText, []diag.Diagnostic{suppressed})
+	assert.Equal(t, "no problems found (1 suppressed)\n", text)
+
+	sarif := renderFormat(t, report.FormatSarif, []diag.Diagnostic{suppressed})
+	assert.Contains(t, sarif, `"justification": "intended"`)
+}
+
+func TestSourceMap(t *testing.T) {
+	src := text.NewSourceText("package a\n")
+	m := report.SourceMap{"a.go": src}
+
+	actual, ok := m.GetSourceText("a.go")
+	assert.True(t, ok)
+	assert.True(t, src == actual)
+
+	_, ok = m.GetSourceText("b.go")
+	assert.False(t, ok)
+}
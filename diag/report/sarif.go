@@ -0,0 +1,289 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// SARIF schema and version of the produced logs
+const (
+	SarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	SarifVersion = "2.1.0"
+)
+
+// srcRootBaseID is the uri base id of relative artifact paths
+const srcRootBaseID = "%SRCROOT%"
+
+// SarifRenderer writes diagnostics as SARIF 2.1.0 log with a single run
+type SarifRenderer struct {
+	w       io.Writer
+	Sources SourceResolver
+	// Descriptors are metadata of rules which reported diagnostics
+	Descriptors    []diag.Descriptor
+	ToolName       string
+	ToolVersion    string
+	InformationURI string
+}
+
+// NewSarifRenderer creates new SARIF renderer
+func NewSarifRenderer(w io.Writer, sources SourceResolver, descriptors []diag.Descriptor) *SarifRenderer {
+	return &SarifRenderer{
+		w:           w,
+		Sources:     sources,
+		Descriptors: descriptors,
+		ToolName:    "goanalyzer",
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool     `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules,omitempty"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name,omitempty"`
+	ShortDescription     *sarifMessage      `json:"shortDescription,omitempty"`
+	Help                 *sarifMessage      `json:"help,omitempty"`
+	HelpURI              string             `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string             `json:"ruleId"`
+	RuleIndex        *int               `json:"ruleIndex,omitempty"`
+	Level            string             `json:"level"`
+	Message          sarifMessage       `json:"message"`
+	Locations        []sarifLocation    `json:"locations"`
+	RelatedLocations []sarifLocation    `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix         `json:"fixes,omitempty"`
+	Suppressions     []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               *int                  `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine,omitempty"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+	ByteOffset  int `json:"byteOffset"`
+	ByteLength  int `json:"byteLength"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion   `json:"deletedRegion"`
+	InsertedContent *sarifMessage `json:"insertedContent,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// Render writes SARIF log with the diagnostics, suppressed diagnostics are written with their suppressions
+func (r *SarifRenderer) Render(diags []diag.Diagnostic) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           r.ToolName,
+				Version:        r.ToolVersion,
+				InformationURI: r.InformationURI,
+			},
+		},
+		ColumnKind: "utf16CodeUnits",
+		Results:    []sarifResult{},
+	}
+
+	ruleIndexes := map[string]int{}
+	for i, d := range r.Descriptors {
+		ruleIndexes[d.ID] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, getSarifRule(d))
+	}
+
+	for _, d := range diags {
+		run.Results = append(run.Results, r.getResult(d, ruleIndexes))
+	}
+
+	log := sarifLog{
+		Schema:  SarifSchema,
+		Version: SarifVersion,
+		Runs:    []sarifRun{run},
+	}
+
+	encoder := json.NewEncoder(r.w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(log)
+}
+
+func getSarifRule(d diag.Descriptor) sarifRule {
+	rule := sarifRule{
+		ID:                   d.ID,
+		Name:                 d.Name,
+		HelpURI:              d.HelpURI,
+		DefaultConfiguration: sarifConfiguration{Level: getSarifLevel(d.DefaultSeverity)},
+	}
+	if d.Title != "" {
+		rule.ShortDescription = &sarifMessage{Text: d.Title}
+	}
+	if d.Help != "" {
+		rule.Help = &sarifMessage{Text: d.Help}
+	}
+	return rule
+}
+
+func (r *SarifRenderer) getResult(d diag.Diagnostic, ruleIndexes map[string]int) sarifResult {
+	result := sarifResult{
+		RuleID:    d.ID,
+		Level:     getSarifLevel(d.Severity),
+		Message:   sarifMessage{Text: d.GetMessage()},
+		Locations: []sarifLocation{r.getLocation(d.Location)},
+	}
+
+	if index, ok := ruleIndexes[d.ID]; ok {
+		result.RuleIndex = &index
+	}
+
+	for i, loc := range d.AdditionalLocations {
+		related := r.getLocation(loc)
+		id := i + 1
+		related.ID = &id
+		if loc.Message != "" {
+			related.Message = &sarifMessage{Text: loc.Message}
+		}
+		result.RelatedLocations = append(result.RelatedLocations, related)
+	}
+
+	for _, fix := range d.Fixes {
+		result.Fixes = append(result.Fixes, r.getFix(d.Location.Path, fix))
+	}
+
+	for _, s := range d.Suppressions {
+		result.Suppressions = append(result.Suppressions, sarifSuppression{Kind: s.Kind.String(), Justification: s.Justification})
+	}
+	return result
+}
+
+func (r *SarifRenderer) getLocation(loc diag.Location) sarifLocation {
+	return sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: getSarifArtifactLocation(loc.Path),
+			Region:           r.getRegion(loc.Path, loc.Span),
+		},
+	}
+}
+
+func (r *SarifRenderer) getFix(path string, fix diag.CodeFix) sarifFix {
+	change := sarifArtifactChange{
+		ArtifactLocation: getSarifArtifactLocation(path),
+		Replacements:     []sarifReplacement{},
+	}
+	for _, c := range fix.Changes {
+		replacement := sarifReplacement{DeletedRegion: r.getRegion(path, c.Span)}
+		if c.NewText != "" {
+			replacement.InsertedContent = &sarifMessage{Text: c.NewText}
+		}
+		change.Replacements = append(change.Replacements, replacement)
+	}
+
+	return sarifFix{
+		Description:     sarifMessage{Text: fix.Title},
+		ArtifactChanges: []sarifArtifactChange{change},
+	}
+}
+
+// getRegion returns region of the span, line and column are set only if the source is available
+func (r *SarifRenderer) getRegion(path string, span text.TextSpan) sarifRegion {
+	region := sarifRegion{
+		ByteOffset: span.Start(),
+		ByteLength: span.Length(),
+	}
+
+	loc := diag.NewLocation(path, span)
+	if _, lineSpan, ok := getLineSpan(r.Sources, loc, text.CharacterUnitUTF16); ok {
+		region.StartLine = lineSpan.Start.Line + 1
+		region.StartColumn = lineSpan.Start.Character + 1
+		region.EndLine = lineSpan.End.Line + 1
+		region.EndColumn = lineSpan.End.Character + 1
+	}
+	return region
+}
+
+func getSarifArtifactLocation(path string) sarifArtifactLocation {
+	if filepath.IsAbs(path) {
+		uri := filepath.ToSlash(path)
+		if !strings.HasPrefix(uri, "/") {
+			uri = "/" + uri
+		}
+		return sarifArtifactLocation{URI: "file://" + uri}
+	}
+	return sarifArtifactLocation{URI: filepath.ToSlash(path), URIBaseID: srcRootBaseID}
+}
+
+func getSarifLevel(severity diag.Severity) string {
+	switch severity {
+	case diag.SeverityError:
+		return "error"
+	case diag.SeverityWarning:
+		return "warning"
+	case diag.SeverityInfo:
+		return "note"
+	}
+	return "none"
+}
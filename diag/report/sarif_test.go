@@ -0,0 +1,68 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/report"
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSarifRenderer(t *testing.T) {
+	descriptors := []diag.Descriptor{
+		{
+			ID:              "GA1000",
+			Name:            "MismatchedTypes",
+			Title:           "Mismatched types",
+			Help:            "Operands of the binary expression must have the same type.",
+			HelpURI:         "https://example.com/GA1000",
+			DefaultSeverity: diag.SeverityError,
+		},
+		{ID: "GA1001", DefaultSeverity: diag.SeverityWarning},
+	}
+
+	d1 := diag.NewDiagnostic("GA1000", diag.SeverityError, diag.NewLocation("a.go", text.NewTextSpan(42, 8)), "mismatched types")
+	d1.Fixes = []diag.CodeFix{
+		diag.NewCodeFix("Use y as string",
+			text.NewTextChange(text.NewTextSpan(53, 1), "string(y)"),
+			text.NewTextChange(text.NewTextSpan(42, 0), "")),
+	}
+
+	d2 := diag.NewDiagnostic("GA1001", diag.SeverityInfo, diag.NewLocation("a.go", text.NewTextSpan(38, 1)), "%s shadows declaration", "x")
+	d2.AdditionalLocations = []diag.Location{diag.NewLabeledLocation("a.go", text.NewTextSpan(15, 1), "declared here")}
+	d2.Suppressions = []diag.Suppression{{Kind: diag.SuppressionInSource, Justification: "intended"}}
+
+	d3 := diag.NewDiagnostic("GA9999", diag.SeverityHidden, diag.NewLocation("/src/c.go", text.NewTextSpan(1, 2)), "no source")
+
+	var buffer bytes.Buffer
+	r := report.NewSarifRenderer(&buffer, newTestSources(), descriptors)
+	r.ToolVersion = "1.0.0"
+	assert.NoError(t, r.Render([]diag.Diagnostic{d1, d2, d3}))
+
+	var log map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log["version"])
+
+	asttest.CheckGolden(t, "testdata/sarif.golden", buffer.String())
+}
+
+func TestSarifRendererEmpty(t *testing.T) {
+	var buffer bytes.Buffer
+	r := report.NewSarifRenderer(&buffer, nil, nil)
+	assert.NoError(t, r.Render(nil))
+
+	var log struct {
+		Runs []struct {
+			Results []interface{} `json:"results"`
+		} `json:"runs"`
+	}
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &log))
+	if assert.Len(t, log.Runs, 1) {
+		assert.NotNil(t, log.Runs[0].Results)
+		assert.Empty(t, log.Runs[0].Results)
+	}
+}
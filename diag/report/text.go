@@ -0,0 +1,229 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// ANSI escape sequences used for coloring
+const (
+	colorReset  = "\x1b[0m"
+	colorBold   = "\x1b[1m"
+	colorRed    = "\x1b[1;31m"
+	colorYellow = "\x1b[1;33m"
+	colorCyan   = "\x1b[1;36m"
+	colorBlue   = "\x1b[34m"
+)
+
+// TextRenderer renders diagnostics for humans: file:line:col header, source excerpt with the span underlined,
+// related locations and the summary line
+type TextRenderer struct {
+	w       io.Writer
+	Sources SourceResolver
+	// Color enables ANSI colors
+	Color bool
+	// Summary enables summary line with number of errors and warnings
+	Summary bool
+}
+
+// NewTextRenderer creates new text renderer, colors are enabled only if w is a terminal
+func NewTextRenderer(w io.Writer, sources SourceResolver) *TextRenderer {
+	return &TextRenderer{
+		w:       w,
+		Sources: sources,
+		Color:   IsColorTerminal(w),
+		Summary: true,
+	}
+}
+
+// IsColorTerminal checks if w is a terminal which supports colors.
+// NO_COLOR environment variable and dumb terminal disable colors.
+func IsColorTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Render writes diagnostics followed by the summary line, suppressed diagnostics are only counted in the summary
+func (r *TextRenderer) Render(diags []diag.Diagnostic) error {
+	p := &textPrinter{w: r.w, color: r.Color}
+	reported := GetUnsuppressed(diags)
+	for i, d := range reported {
+		if i > 0 {
+			p.printf("\n")
+		}
+		r.renderDiagnostic(p, d)
+	}
+
+	if r.Summary {
+		if len(reported) > 0 {
+			p.printf("\n")
+		}
+		p.printf("%s\n", FormatSummary(GetSummary(diags)))
+	}
+	return p.err
+}
+
+// FormatSummary returns summary line, e.g. "2 errors, 1 warning"
+func FormatSummary(s Summary) string {
+	parts := []string{}
+	parts = appendCount(parts, s.Errors, "error")
+	parts = appendCount(parts, s.Warnings, "warning")
+	parts = appendCount(parts, s.Infos, "info")
+	summary := strings.Join(parts, ", ")
+	if len(parts) == 0 {
+		summary = "no problems found"
+	}
+	if s.Suppressed > 0 {
+		summary += fmt.Sprintf(" (%d suppressed)", s.Suppressed)
+	}
+	return summary
+}
+
+func appendCount(parts []string, count int, name string) []string {
+	if count == 0 {
+		return parts
+	}
+	if count != 1 {
+		name += "s"
+	}
+	return append(parts, fmt.Sprintf("%d %s", count, name))
+}
+
+func (r *TextRenderer) renderDiagnostic(p *textPrinter, d diag.Diagnostic) {
+	src, span, ok := getLineSpan(r.Sources, d.Location, text.CharacterUnitByte)
+	severity := p.colored(getSeverityColor(d.Severity), d.Severity.String())
+	if ok {
+		p.printf("%s: %s %s: %s\n", p.colored(colorBold, formatLinePosition(d.Location.Path, span.Start)), severity, d.ID, d.GetMessage())
+	} else {
+		p.printf("%s: %s %s: %s\n", p.colored(colorBold, d.Location.Path), severity, d.ID, d.GetMessage())
+	}
+
+	width := getGutterWidth(r.Sources, d)
+	if ok {
+		r.renderExcerpt(p, src, span, width, "^", "~", getSeverityColor(d.Severity), "")
+	}
+
+	for _, loc := range d.AdditionalLocations {
+		src, span, ok := getLineSpan(r.Sources, loc, text.CharacterUnitByte)
+		if !ok {
+			p.printf("%s %s: %s\n", p.colored(colorBlue, strings.Repeat(" ", width)+" -->"), loc.Path, loc.Message)
+			continue
+		}
+
+		if loc.Path != d.Location.Path {
+			p.printf("%s %s\n", p.colored(colorBlue, strings.Repeat(" ", width)+" -->"), formatLinePosition(loc.Path, span.Start))
+		}
+		r.renderExcerpt(p, src, span, width, "-", "-", colorCyan, loc.Message)
+	}
+}
+
+// renderExcerpt prints the first line of the span and underlines the span part of the line
+func (r *TextRenderer) renderExcerpt(p *textPrinter, src *text.SourceText, lineSpan text.LinePositionSpan,
+	width int, first string, rest string, color string, label string) {
+	line := lineSpan.Start.Line
+	lineText := src.GetLineText(line)
+	start := lineSpan.Start.Character
+	end := len(lineText)
+	if lineSpan.End.Line == line && lineSpan.End.Character < end {
+		end = lineSpan.End.Character
+	}
+
+	gutter := p.colored(colorBlue, fmt.Sprintf("%*d |", width, line+1))
+	p.printf("%s %s\n", gutter, lineText)
+
+	underline := getUnderlinePrefix(lineText[:start]) + getUnderline(lineText[start:end], first, rest)
+	if label != "" {
+		underline += " " + label
+	}
+	p.printf("%s %s\n", p.colored(colorBlue, strings.Repeat(" ", width)+" |"), p.colored(color, underline))
+}
+
+// getUnderlinePrefix returns whitespace which aligns underline with the text after prefix, tabs are kept as is
+func getUnderlinePrefix(prefix string) string {
+	var b strings.Builder
+	for _, c := range prefix {
+		if c == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+func getUnderline(str string, first string, rest string) string {
+	n := utf8.RuneCountInString(str)
+	if n == 0 {
+		return first
+	}
+	return first + strings.Repeat(rest, n-1)
+}
+
+func getGutterWidth(sources SourceResolver, d diag.Diagnostic) int {
+	width := 1
+	locations := append([]diag.Location{d.Location}, d.AdditionalLocations...)
+	for _, loc := range locations {
+		if _, span, ok := getLineSpan(sources, loc, text.CharacterUnitByte); ok {
+			if w := len(strconv.Itoa(span.Start.Line + 1)); w > width {
+				width = w
+			}
+		}
+	}
+	return width
+}
+
+func formatLinePosition(path string, pos text.LinePosition) string {
+	return fmt.Sprintf("%s:%d:%d", path, pos.Line+1, pos.Character+1)
+}
+
+func getSeverityColor(severity diag.Severity) string {
+	switch severity {
+	case diag.SeverityError:
+		return colorRed
+	case diag.SeverityWarning:
+		return colorYellow
+	case diag.SeverityInfo:
+		return colorCyan
+	}
+	return ""
+}
+
+// textPrinter writes formatted text and remembers the first write error
+type textPrinter struct {
+	w     io.Writer
+	color bool
+	err   error
+}
+
+func (p *textPrinter) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, args...)
+}
+
+func (p *textPrinter) colored(color string, str string) string {
+	if !p.color || color == "" {
+		return str
+	}
+	return color + str + colorReset
+}
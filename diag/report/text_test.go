@@ -0,0 +1,122 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/report"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSrc = "package a\n\nvar x = 1\n\nfunc f() {\n\tvar x = \"日本\" + y\n}\n"
+
+func newTestSources() report.SourceMap {
+	return report.SourceMap{
+		"a.go": text.NewSourceText(testSrc),
+		"b.go": text.NewSourceText("package a\n\nvar z int\n"),
+	}
+}
+
+func renderText(t *testing.T, sources report.SourceResolver, diags ...diag.Diagnostic) string {
+	var buffer bytes.Buffer
+	r := report.NewTextRenderer(&buffer, sources)
+	assert.False(t, r.Color)
+	assert.NoError(t, r.Render(diags))
+	return buffer.String()
+}
+
+func TestTextRenderer(t *testing.T) {
+	d := diag.NewDiagnostic("GA1000", diag.SeverityError, diag.NewLocation("a.go", text.NewTextSpan(42, 8)), "mismatched types")
+	expected := "" +
+		"a.go:6:10: error GA1000: mismatched types\n" +
+		"6 | \tvar x = \"日本\" + y\n" +
+		"  | \t        ^~~~\n" +
+		"\n" +
+		"1 error\n"
+	assert.Equal(t, expected, renderText(t, newTestSources(), d))
+}
+
+func TestTextRendererAdditionalLocations(t *testing.T) {
+	d := diag.NewDiagnostic("GA1001", diag.SeverityWarning, diag.NewLocation("a.go", text.NewTextSpan(38, 1)), "%s shadows declaration", "x")
+	d.AdditionalLocations = []diag.Location{
+		diag.NewLabeledLocation("a.go", text.NewTextSpan(15, 1), "declared here"),
+		diag.NewLabeledLocation("b.go", text.NewTextSpan(15, 3), "also here"),
+		diag.NewLabeledLocation("c.go", text.NewTextSpan(0, 1), "unknown file"),
+	}
+	info := diag.NewDiagnostic("GA1002", diag.SeverityInfo, diag.NewLocation("a.go", text.NewTextSpan(0, 0)), "info")
+
+	expected := "" +
+		"a.go:6:6: warning GA1001: x shadows declaration\n" +
+		"6 | \tvar x = \"日本\" + y\n" +
+		"  | \t    ^\n" +
+		"3 | var x = 1\n" +
+		"  |     - declared here\n" +
+		"  --> b.go:3:5\n" +
+		"3 | var z int\n" +
+		"  |     --- also here\n" +
+		"  --> c.go: unknown file\n" +
+		"\n" +
+		"a.go:1:1: info GA1002: info\n" +
+		"1 | package a\n" +
+		"  | ^\n" +
+		"\n" +
+		"1 warning, 1 info\n"
+	assert.Equal(t, expected, renderText(t, newTestSources(), d, info))
+}
+
+func TestTextRendererMultilineSpan(t *testing.T) {
+	d := diag.NewDiagnostic("GA1003", diag.SeverityError, diag.NewLocation("a.go", text.NewTextSpan(22, 22)), "bad func")
+	expected := "" +
+		"a.go:5:1: error GA1003: bad func\n" +
+		"5 | func f() {\n" +
+		"  | ^~~~~~~~~~\n" +
+		"\n" +
+		"1 error\n"
+	assert.Equal(t, expected, renderText(t, newTestSources(), d))
+}
+
+func TestTextRendererWithoutSource(t *testing.T) {
+	d := diag.NewDiagnostic("GA1000", diag.SeverityError, diag.NewLocation("x.go", text.NewTextSpan(0, 1)), "message")
+	assert.Equal(t, "x.go: error GA1000: message\n\n1 error\n", renderText(t, nil, d))
+	assert.Equal(t, "no problems found\n", renderText(t, nil))
+}
+
+func TestTextRendererColor(t *testing.T) {
+	var buffer bytes.Buffer
+	r := report.NewTextRenderer(&buffer, newTestSources())
+	r.Color = true
+	r.Summary = false
+	d := diag.NewDiagnostic("GA1000", diag.SeverityWarning, diag.NewLocation("a.go", text.NewTextSpan(15, 1)), "message")
+	assert.NoError(t, r.Render([]diag.Diagnostic{d}))
+
+	expected := "" +
+		"\x1b[1ma.go:3:5\x1b[0m: \x1b[1;33mwarning\x1b[0m GA1000: message\n" +
+		"\x1b[34m3 |\x1b[0m var x = 1\n" +
+		"\x1b[34m  |\x1b[0m \x1b[1;33m    ^\x1b[0m\n"
+	assert.Equal(t, expected, buffer.String())
+}
+
+func TestTextRendererDocumentRegistry(t *testing.T) {
+	registry := syntax.NewDocumentRegistry()
+	_, err := registry.AddDocument("a.go", "package a\n\nvar x = )\n")
+	assert.Error(t, err)
+
+	doc := registry.GetDocument("a.go")
+	expected := "" +
+		"a.go:3:9: error GA0001: expected operand, found ')'\n" +
+		"3 | var x = )\n" +
+		"  |         ^\n" +
+		"\n" +
+		"1 error\n"
+	assert.Equal(t, expected, renderText(t, registry, doc.Diagnostics...))
+}
+
+func TestFormatSummary(t *testing.T) {
+	assert.Equal(t, "2 errors, 3 infos", report.FormatSummary(report.Summary{Errors: 2, Infos: 3, Hidden: 1}))
+	assert.Equal(t, "no problems found", report.FormatSummary(report.Summary{Hidden: 1}))
+	assert.Equal(t, "1 warning (2 suppressed)", report.FormatSummary(report.Summary{Warnings: 1, Suppressed: 2}))
+	assert.Equal(t, "no problems found (1 suppressed)", report.FormatSummary(report.Summary{Suppressed: 1}))
+}
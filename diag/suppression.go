@@ -0,0 +1,29 @@
+package diag
+
+// SuppressionKind denotes where the suppression is declared
+type SuppressionKind int
+
+// Suppression kinds
+const (
+	// SuppressionInSource is the suppression declared in the source code, e.g. with a comment directive
+	SuppressionInSource SuppressionKind = iota
+	// SuppressionExternal is the suppression declared outside of the source code, e.g. in a baseline file
+	SuppressionExternal
+)
+
+// String returns string representation of the suppression kind
+func (k SuppressionKind) String() string {
+	switch k {
+	case SuppressionInSource:
+		return "inSource"
+	case SuppressionExternal:
+		return "external"
+	}
+	return "none"
+}
+
+// Suppression represents suppression of the diagnostic
+type Suppression struct {
+	Kind          SuppressionKind
+	Justification string
+}
@@ -0,0 +1,292 @@
+package asttest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/scanner"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/a6cexz/goanalyzer/diag/text/helpers"
+)
+
+// Analyzer analyzes document and returns found diagnostics
+type Analyzer interface {
+	Analyze(doc *syntax.Document) []diag.Diagnostic
+}
+
+// AnalyzerFunc adapts function to Analyzer interface
+type AnalyzerFunc func(doc *syntax.Document) []diag.Diagnostic
+
+// Analyze calls f(doc)
+func (f AnalyzerFunc) Analyze(doc *syntax.Document) []diag.Diagnostic {
+	return f(doc)
+}
+
+// Testing is the part of *testing.T used by RunAnalyzer
+type Testing interface {
+	Errorf(format string, args ...interface{})
+}
+
+type wantExpectation struct {
+	line    int
+	rx      *regexp.Regexp
+	matched bool
+}
+
+type spanExpectation struct {
+	span    text.TextSpan
+	line    int
+	matched bool
+}
+
+type analyzedDiagnostic struct {
+	diag.Diagnostic
+	line        int
+	matchedWant bool
+	matchedSpan bool
+}
+
+// RunAnalyzer runs the analyzer on each .go fixture in the directory and its subdirectories and checks
+// reported diagnostics. Fixtures mark expected diagnostics with // want "regex" comments, which expect
+// a diagnostic with matching message on the comment line, and with [|...|] spans, which expect a diagnostic
+// exactly at the span. Missing, unexpected and mis-positioned diagnostics are reported as errors.
+// Suppressed diagnostics are ignored. If diagnostics have code fixes the source fixed with the first fix
+// of each diagnostic is compared with the fixture .golden file, identical changes of different fixes are applied once.
+// A .golden file of the fixture without fixes is reported as error.
+func RunAnalyzer(t Testing, analyzer Analyzer, dir string) {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+
+	paths, err := getAnalyzerFixtures(dir)
+	if err != nil {
+		t.Errorf("cannot read fixtures: %v", err)
+		return
+	}
+
+	if len(paths) == 0 {
+		t.Errorf("no .go fixtures found in %s", dir)
+		return
+	}
+
+	registry := syntax.NewDocumentRegistry()
+	for _, path := range paths {
+		runAnalyzerFixture(t, analyzer, registry, path)
+	}
+}
+
+func getAnalyzerFixtures(dir string) ([]string, error) {
+	paths := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && filepath.Ext(path) == ".go" {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+func runAnalyzerFixture(t Testing, analyzer Analyzer, registry *syntax.DocumentRegistry, path string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	src, m, err := helpers.ParseMarkup(string(content))
+	if err != nil {
+		t.Errorf("%s: %v", path, err)
+		return
+	}
+
+	doc, err := registry.AddDocument(path, src)
+	if err != nil {
+		t.Errorf("%s: %v", path, err)
+		return
+	}
+
+	wants, err := getWantExpectations(doc)
+	if err != nil {
+		t.Errorf("%v", err)
+		return
+	}
+
+	spans := []*spanExpectation{}
+	for _, span := range m[helpers.AnonymousName] {
+		spans = append(spans, &spanExpectation{span: span, line: doc.Text.GetLineFromPosition(span.Start())})
+	}
+
+	diags := []*analyzedDiagnostic{}
+	changes := []text.TextChange{}
+	for _, d := range analyzer.Analyze(doc) {
+		if d.IsSuppressed() {
+			continue
+		}
+		diags = append(diags, &analyzedDiagnostic{Diagnostic: d, line: doc.Text.GetLineFromPosition(d.Location.Span.Start())})
+		if len(d.Fixes) > 0 {
+			changes = appendChanges(changes, d.Fixes[0].Changes)
+		}
+	}
+
+	checkDiagnostics(t, doc, diags, wants, spans)
+
+	if len(changes) > 0 {
+		checkSuggestedFixes(t, doc, path, changes)
+	} else if _, err := os.Stat(GetGoldenPath(path)); err == nil {
+		t.Errorf("%s: golden file %s exists but no fixes were suggested", path, GetGoldenPath(path))
+	}
+}
+
+// appendChanges appends changes which are not in the list yet, e.g. diagnostics of different rules
+// may suggest the same fix, it is applied once as by codefix.ApplyToDocument
+func appendChanges(changes []text.TextChange, added []text.TextChange) []text.TextChange {
+	for _, change := range added {
+		duplicate := false
+		for _, prev := range changes {
+			if prev == change {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func checkDiagnostics(t Testing, doc *syntax.Document, diags []*analyzedDiagnostic, wants []*wantExpectation, spans []*spanExpectation) {
+	for _, d := range diags {
+		for _, s := range spans {
+			if !s.matched && s.span.Equals(d.Location.Span) {
+				s.matched = true
+				d.matchedSpan = true
+				break
+			}
+		}
+
+		for _, w := range wants {
+			if !w.matched && w.line == d.line && w.rx.MatchString(d.GetMessage()) {
+				w.matched = true
+				d.matchedWant = true
+				break
+			}
+		}
+	}
+
+	for _, s := range spans {
+		if s.matched {
+			continue
+		}
+
+		var misplaced *analyzedDiagnostic
+		for _, d := range diags {
+			if !d.matchedSpan && d.line == s.line {
+				misplaced = d
+				break
+			}
+		}
+
+		if misplaced != nil {
+			misplaced.matchedSpan = true
+			t.Errorf("%s: diagnostic %q is mis-positioned: got %v, want %v",
+				formatPos(doc, misplaced.Location.Span), misplaced.GetMessage(), misplaced.Location.Span, s.span)
+		} else {
+			t.Errorf("%s: missing diagnostic at %v", formatPos(doc, s.span), s.span)
+		}
+	}
+
+	for _, w := range wants {
+		if !w.matched {
+			t.Errorf("%s:%d: missing diagnostic matching %q", doc.Path, w.line+1, w.rx)
+		}
+	}
+
+	for _, d := range diags {
+		if !d.matchedSpan && !d.matchedWant {
+			t.Errorf("%s: unexpected diagnostic: %s", formatPos(doc, d.Location.Span), d.GetMessage())
+		}
+	}
+}
+
+func checkSuggestedFixes(t Testing, doc *syntax.Document, path string, changes []text.TextChange) {
+	fixed, err := doc.Text.WithChanges(changes...)
+	if err != nil {
+		t.Errorf("%s: cannot apply suggested fixes: %v", path, err)
+		return
+	}
+
+	if err := checkGolden(GetGoldenPath(path), fixed.String()); err != nil {
+		t.Errorf("%s: %v", path, err)
+	}
+}
+
+func formatPos(doc *syntax.Document, span text.TextSpan) string {
+	pos := doc.Text.GetLinePosition(span.Start(), text.CharacterUnitByte)
+	return fmt.Sprintf("%s:%d:%d", doc.Path, pos.Line+1, pos.Character+1)
+}
+
+const wantPrefix = "// want "
+
+func getWantExpectations(doc *syntax.Document) ([]*wantExpectation, error) {
+	wants := []*wantExpectation{}
+	for _, group := range doc.AstFile.Comments {
+		for _, c := range group.List {
+			if !strings.HasPrefix(c.Text, wantPrefix) {
+				continue
+			}
+
+			offset := doc.GetOffset(c.Slash)
+			line := doc.Text.GetLineFromPosition(offset)
+			patterns, err := parseWantPatterns(c.Text[len(wantPrefix):])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid want comment: %v", doc.Path, line+1, err)
+			}
+
+			for _, p := range patterns {
+				rx, err := regexp.Compile(p)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: invalid want pattern: %v", doc.Path, line+1, err)
+				}
+				wants = append(wants, &wantExpectation{line: line, rx: rx})
+			}
+		}
+	}
+	return wants, nil
+}
+
+func parseWantPatterns(str string) ([]string, error) {
+	var s scanner.Scanner
+	s.Init(strings.NewReader(str))
+	s.Mode = scanner.ScanStrings | scanner.ScanRawStrings
+	s.Error = func(*scanner.Scanner, string) {}
+
+	patterns := []string{}
+	for tok := s.Scan(); tok != scanner.EOF; tok = s.Scan() {
+		if tok != scanner.String && tok != scanner.RawString {
+			return nil, fmt.Errorf("expected string literal, got %q", s.TokenText())
+		}
+		p, err := strconv.Unquote(s.TokenText())
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no patterns")
+	}
+	return patterns, nil
+}
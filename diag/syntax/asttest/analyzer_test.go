@@ -0,0 +1,101 @@
+package asttest_test
+
+import (
+	"fmt"
+	"go/ast"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRecorder struct {
+	errors []string
+}
+
+func (r *testRecorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func collectIdents(node syntax.Node, idents []syntax.Node) []syntax.Node {
+	if _, ok := node.GetAstNode().(*ast.Ident); ok {
+		idents = append(idents, node)
+	}
+	for _, elmt := range node.GetElements() {
+		if child, ok := elmt.(syntax.Node); ok {
+			idents = collectIdents(child, idents)
+		}
+	}
+	return idents
+}
+
+// printAnalyzer reports println calls and suggests to replace them with print
+var printAnalyzer = asttest.AnalyzerFunc(func(doc *syntax.Document) []diag.Diagnostic {
+	diags := []diag.Diagnostic{}
+	for _, ident := range collectIdents(doc.Root, nil) {
+		name := ident.GetAstNode().(*ast.Ident).Name
+		if name != "println" {
+			continue
+		}
+		span := doc.GetSpan(ident)
+		d := diag.NewDiagnostic("GA9000", diag.SeverityWarning, diag.NewLocation(doc.Path, span), "avoid %s", name)
+		d.Fixes = []diag.CodeFix{diag.NewCodeFix("Use print", text.NewTextChange(span, "print"))}
+		diags = append(diags, d)
+	}
+	return diags
+})
+
+// anyPrintAnalyzer reports print and println calls
+var anyPrintAnalyzer = asttest.AnalyzerFunc(func(doc *syntax.Document) []diag.Diagnostic {
+	diags := []diag.Diagnostic{}
+	for _, ident := range collectIdents(doc.Root, nil) {
+		name := ident.GetAstNode().(*ast.Ident).Name
+		if name == "println" || name == "print" {
+			location := diag.NewLocation(doc.Path, doc.GetSpan(ident))
+			diags = append(diags, diag.NewDiagnostic("GA9001", diag.SeverityWarning, location, "avoid %s", name))
+		}
+	}
+	return diags
+})
+
+// twicePrintAnalyzer reports println calls by two rules with the same fix
+var twicePrintAnalyzer = asttest.AnalyzerFunc(func(doc *syntax.Document) []diag.Diagnostic {
+	diags := printAnalyzer(doc)
+	for _, d := range printAnalyzer(doc) {
+		d.ID = "GA9002"
+		diags = append(diags, d)
+	}
+	return diags
+})
+
+func TestRunAnalyzer(t *testing.T) {
+	asttest.RunAnalyzer(t, printAnalyzer, "testdata/analyzer/ok")
+}
+
+func TestRunAnalyzerErrors(t *testing.T) {
+	r := &testRecorder{}
+	asttest.RunAnalyzer(r, anyPrintAnalyzer, "testdata/analyzer/fail")
+	assert.Equal(t, []string{
+		`testdata/analyzer/fail/b.go:5:2: diagnostic "avoid print" is mis-positioned: got [62..67), want [68..71)`,
+		`testdata/analyzer/fail/b.go:7:2: missing diagnostic at [86..89)`,
+		`testdata/analyzer/fail/b.go:4: missing diagnostic matching "something else"`,
+		`testdata/analyzer/fail/b.go:4:2: unexpected diagnostic: avoid println`,
+	}, r.errors)
+
+	r = &testRecorder{}
+	asttest.RunAnalyzer(r, anyPrintAnalyzer, "testdata/analyzer/none")
+	assert.Equal(t, 1, len(r.errors))
+
+	r = &testRecorder{}
+	asttest.RunAnalyzer(r, anyPrintAnalyzer, "testdata/analyzer/nofix")
+	assert.Equal(t, []string{
+		"testdata/analyzer/nofix/c.go: golden file testdata/analyzer/nofix/c.golden exists but no fixes were suggested",
+	}, r.errors)
+}
+
+func TestRunAnalyzer_DuplicateFixes(t *testing.T) {
+	asttest.RunAnalyzer(t, twicePrintAnalyzer, "testdata/analyzer/duplicate")
+}
@@ -2,22 +2,21 @@ package asttest
 
 import (
 	"go/ast"
-	"go/parser"
 	"go/token"
 
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
 	"github.com/a6cexz/goanalyzer/diag/text"
 	"github.com/a6cexz/goanalyzer/diag/text/helpers"
 	"golang.org/x/tools/go/ast/astutil"
 )
 
-// ParseTestFile parses test file
-func ParseTestFile(src string) (*token.FileSet, *ast.File, text.TextSpan, error) {
+// ParseTestFile parses test file with #start# and #end# markers.
+// The ast is always returned, syntax errors are returned as diagnostics.
+func ParseTestFile(src string) (*token.FileSet, *ast.File, text.TextSpan, []diag.Diagnostic) {
 	src, m := helpers.RemoveTextMarkers(src)
 	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "source.go", src, parser.ParseComments)
-	if err != nil {
-		return fset, file, text.NewTextSpan(0, 0), err
-	}
+	file, _, diags := syntax.ParseFile(fset, "source.go", src)
 
 	start := 0
 	if pos, ok := m["#start#"]; ok {
@@ -30,20 +29,28 @@ func ParseTestFile(src string) (*token.FileSet, *ast.File, text.TextSpan, error)
 	}
 
 	s := text.NewTextSpanFromBounds(start, end)
-	return fset, file, s, nil
+	return fset, file, s, diags
 }
 
-// GetTestAstNode gets test node from source
-func GetTestAstNode(src string) (ast.Node, error) {
-	_, file, span, err := ParseTestFile(src)
-	if err != nil {
-		return nil, err
+// GetTestAstNode gets the innermost ast node which contains the span marked with #start# and #end#
+func GetTestAstNode(src string) (ast.Node, []diag.Diagnostic) {
+	fset, file, span, diags := ParseTestFile(src)
+	// file set has only the parsed file, file.Pos() is not valid if package clause is missing
+	var tokFile *token.File
+	fset.Iterate(func(f *token.File) bool {
+		tokFile = f
+		return false
+	})
+	if tokFile == nil {
+		return nil, diags
 	}
 
-	start := token.Pos(span.Start())
-	end := token.Pos(span.End())
+	start := tokFile.Pos(span.Start())
+	end := tokFile.Pos(span.End())
 	path, _ := astutil.PathEnclosingInterval(file, start, end)
-	node := path[0]
+	if len(path) == 0 {
+		return nil, diags
+	}
 
-	return node, nil
+	return path[0], diags
 }
@@ -3,8 +3,11 @@ package asttest_test
 import (
 	"testing"
 
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
 	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
 	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+	"github.com/a6cexz/goanalyzer/diag/text"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -12,8 +15,26 @@ func TestParseTestFile(t *testing.T) {
 	src := `package main
 var a = 1#start#0
 `
-	node, err := asttest.GetTestAstNode(src)
-	assert.NoError(t, err)
+	node, diags := asttest.GetTestAstNode(src)
+	assert.Empty(t, diags)
 	assert.True(t, syntaxkind.IsExpr(node))
 	assert.True(t, syntaxkind.AsExpr(node) != nil)
 }
+
+func TestParseTestFileSyntaxError(t *testing.T) {
+	src := `package main
+var a = #start#)
+`
+	_, file, span, diags := asttest.ParseTestFile(src)
+	assert.NotNil(t, file)
+	assert.Equal(t, text.NewTextSpan(21, 0), span)
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, syntax.SyntaxErrorID, diags[0].ID)
+		assert.Equal(t, diag.SeverityError, diags[0].Severity)
+		assert.Equal(t, "expected operand, found ')'", diags[0].GetMessage())
+		assert.Equal(t, text.NewTextSpan(21, 1), diags[0].Location.Span)
+	}
+
+	node, _ := asttest.GetTestAstNode(src)
+	assert.Equal(t, syntaxkind.AstBadExpr, syntaxkind.GetAstKind(node))
+}
@@ -0,0 +1,512 @@
+package asttest
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"math/rand"
+	"strconv"
+)
+
+// ProgramGenerator generates random syntactically valid Go programs
+type ProgramGenerator struct {
+	rnd      *rand.Rand
+	depth    int
+	maxDepth int
+	names    int
+	// noCompositeLit is set while generating if, for and switch headers where composite literals are ambiguous
+	noCompositeLit bool
+}
+
+// NewProgramGenerator creates new program generator
+func NewProgramGenerator(seed int64) *ProgramGenerator {
+	return &ProgramGenerator{
+		rnd:      rand.New(rand.NewSource(seed)),
+		maxDepth: 4,
+	}
+}
+
+// GenerateProgram generates program source for the given seed
+func GenerateProgram(seed int64) string {
+	return NewProgramGenerator(seed).Generate()
+}
+
+// Generate generates formatted program source
+func (g *ProgramGenerator) Generate() string {
+	file := g.GenerateFile()
+	var buffer bytes.Buffer
+	if err := printer.Fprint(&buffer, token.NewFileSet(), file); err != nil {
+		panic(fmt.Sprintf("cannot format generated program: %v", err))
+	}
+	return buffer.String()
+}
+
+// GenerateFile generates program ast
+func (g *ProgramGenerator) GenerateFile() *ast.File {
+	file := &ast.File{Name: ast.NewIdent("p" + strconv.Itoa(g.rnd.Intn(10)))}
+	if g.chance(2) {
+		file.Decls = append(file.Decls, g.importDecl())
+	}
+	count := 1 + g.rnd.Intn(6)
+	for i := 0; i < count; i++ {
+		file.Decls = append(file.Decls, g.decl())
+	}
+	return file
+}
+
+func (g *ProgramGenerator) chance(n int) bool {
+	return g.rnd.Intn(n) == 0
+}
+
+func (g *ProgramGenerator) name() *ast.Ident {
+	g.names++
+	return ast.NewIdent(fmt.Sprintf("%c%d", 'a'+rune(g.rnd.Intn(26)), g.names))
+}
+
+func (g *ProgramGenerator) idents(max int) []*ast.Ident {
+	count := 1 + g.rnd.Intn(max)
+	idents := []*ast.Ident{}
+	for i := 0; i < count; i++ {
+		idents = append(idents, g.name())
+	}
+	return idents
+}
+
+func (g *ProgramGenerator) enter() bool {
+	if g.depth >= g.maxDepth {
+		return false
+	}
+	g.depth++
+	return true
+}
+
+func (g *ProgramGenerator) leave() {
+	g.depth--
+}
+
+func (g *ProgramGenerator) importDecl() ast.Decl {
+	paths := []string{"fmt", "strings", "os", "io", "sort"}
+	d := &ast.GenDecl{Tok: token.IMPORT}
+	count := 1 + g.rnd.Intn(len(paths))
+	for i := 0; i < count; i++ {
+		spec := &ast.ImportSpec{Path: g.stringLit(paths[i])}
+		if g.chance(3) {
+			spec.Name = g.name()
+		}
+		d.Specs = append(d.Specs, spec)
+	}
+	if count > 1 {
+		d.Lparen = 1
+	}
+	return d
+}
+
+func (g *ProgramGenerator) decl() ast.Decl {
+	switch g.rnd.Intn(4) {
+	case 0:
+		return g.valueDecl(token.CONST)
+	case 1:
+		return g.valueDecl(token.VAR)
+	case 2:
+		return g.typeDecl()
+	}
+	return g.funcDecl()
+}
+
+func (g *ProgramGenerator) valueDecl(tok token.Token) *ast.GenDecl {
+	d := &ast.GenDecl{Tok: tok}
+	count := 1 + g.rnd.Intn(3)
+	for i := 0; i < count; i++ {
+		spec := &ast.ValueSpec{Names: g.idents(3)}
+		if tok == token.VAR && g.chance(2) {
+			spec.Type = g.typeExpr()
+		}
+		if tok == token.CONST || spec.Type == nil || g.chance(2) {
+			for range spec.Names {
+				spec.Values = append(spec.Values, g.expr())
+			}
+		}
+		d.Specs = append(d.Specs, spec)
+	}
+	if count > 1 || g.chance(4) {
+		d.Lparen = 1
+	}
+	return d
+}
+
+func (g *ProgramGenerator) typeDecl() *ast.GenDecl {
+	d := &ast.GenDecl{Tok: token.TYPE}
+	count := 1 + g.rnd.Intn(3)
+	for i := 0; i < count; i++ {
+		spec := &ast.TypeSpec{Name: g.name()}
+		if g.chance(4) {
+			spec.Assign = 1
+		}
+		switch g.rnd.Intn(3) {
+		case 0:
+			spec.Type = g.structType()
+		case 1:
+			spec.Type = g.interfaceType()
+		default:
+			spec.Type = g.typeExpr()
+		}
+		d.Specs = append(d.Specs, spec)
+	}
+	if count > 1 {
+		d.Lparen = 1
+	}
+	return d
+}
+
+func (g *ProgramGenerator) funcDecl() *ast.FuncDecl {
+	d := &ast.FuncDecl{Name: g.name(), Type: g.funcType()}
+	if g.chance(3) {
+		recv := &ast.Field{Type: &ast.StarExpr{X: g.name()}}
+		if g.chance(2) {
+			recv.Names = []*ast.Ident{g.name()}
+		}
+		d.Recv = &ast.FieldList{List: []*ast.Field{recv}}
+	}
+	d.Body = g.blockStmt()
+	return d
+}
+
+func (g *ProgramGenerator) funcType() *ast.FuncType {
+	t := &ast.FuncType{Params: &ast.FieldList{}}
+	count := g.rnd.Intn(3)
+	for i := 0; i < count; i++ {
+		t.Params.List = append(t.Params.List, &ast.Field{Names: g.idents(2), Type: g.typeExpr()})
+	}
+	if count > 0 && g.chance(4) {
+		t.Params.List = append(t.Params.List, &ast.Field{
+			Names: []*ast.Ident{g.name()},
+			Type:  &ast.Ellipsis{Elt: g.typeExpr()},
+		})
+	}
+
+	switch g.rnd.Intn(3) {
+	case 0:
+		t.Results = &ast.FieldList{List: []*ast.Field{{Type: g.typeExpr()}}}
+	case 1:
+		t.Results = &ast.FieldList{Opening: 1, Closing: 1}
+		count := 1 + g.rnd.Intn(2)
+		named := g.chance(2)
+		for i := 0; i < count; i++ {
+			f := &ast.Field{Type: g.typeExpr()}
+			if named {
+				f.Names = []*ast.Ident{g.name()}
+			}
+			t.Results.List = append(t.Results.List, f)
+		}
+	}
+	return t
+}
+
+func (g *ProgramGenerator) structType() *ast.StructType {
+	t := &ast.StructType{Fields: &ast.FieldList{}}
+	count := g.rnd.Intn(4)
+	for i := 0; i < count; i++ {
+		f := &ast.Field{Type: g.typeExpr()}
+		if g.chance(4) {
+			f.Type = g.name()
+		} else {
+			f.Names = g.idents(2)
+		}
+		if g.chance(3) {
+			f.Tag = &ast.BasicLit{Kind: token.STRING, Value: "`json:\"" + g.name().Name + "\"`"}
+		}
+		t.Fields.List = append(t.Fields.List, f)
+	}
+	return t
+}
+
+func (g *ProgramGenerator) interfaceType() *ast.InterfaceType {
+	t := &ast.InterfaceType{Methods: &ast.FieldList{}}
+	count := g.rnd.Intn(3)
+	for i := 0; i < count; i++ {
+		f := &ast.Field{Type: g.name()}
+		if !g.chance(4) {
+			f.Names = []*ast.Ident{g.name()}
+			f.Type = g.funcType()
+		}
+		t.Methods.List = append(t.Methods.List, f)
+	}
+	return t
+}
+
+func (g *ProgramGenerator) typeExpr() ast.Expr {
+	if !g.enter() {
+		return ast.NewIdent("int")
+	}
+	defer g.leave()
+
+	switch g.rnd.Intn(10) {
+	case 0:
+		return &ast.StarExpr{X: g.typeExpr()}
+	case 1:
+		return &ast.ArrayType{Elt: g.typeExpr()}
+	case 2:
+		return &ast.ArrayType{Len: g.intLit(), Elt: g.typeExpr()}
+	case 3:
+		return &ast.MapType{Key: g.typeExpr(), Value: g.typeExpr()}
+	case 4:
+		dir := []ast.ChanDir{ast.SEND | ast.RECV, ast.SEND, ast.RECV}[g.rnd.Intn(3)]
+		return &ast.ChanType{Dir: dir, Value: ast.NewIdent("int")}
+	case 5:
+		return &ast.SelectorExpr{X: ast.NewIdent("io"), Sel: ast.NewIdent("Reader")}
+	case 6:
+		return g.funcType()
+	}
+	return ast.NewIdent([]string{"int", "string", "bool", "error", "float64"}[g.rnd.Intn(5)])
+}
+
+func (g *ProgramGenerator) intLit() *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.INT, Value: strconv.Itoa(g.rnd.Intn(100))}
+}
+
+func (g *ProgramGenerator) stringLit(s string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+func (g *ProgramGenerator) basicLit() *ast.BasicLit {
+	switch g.rnd.Intn(6) {
+	case 0:
+		return &ast.BasicLit{Kind: token.FLOAT, Value: "1.5"}
+	case 1:
+		return &ast.BasicLit{Kind: token.IMAG, Value: "2i"}
+	case 2:
+		return &ast.BasicLit{Kind: token.CHAR, Value: "'x'"}
+	case 3:
+		return g.stringLit("s" + strconv.Itoa(g.rnd.Intn(10)) + "\n")
+	case 4:
+		return &ast.BasicLit{Kind: token.STRING, Value: "`raw`"}
+	}
+	return g.intLit()
+}
+
+func (g *ProgramGenerator) exprs(max int) []ast.Expr {
+	count := g.rnd.Intn(max + 1)
+	exprs := []ast.Expr{}
+	for i := 0; i < count; i++ {
+		exprs = append(exprs, g.expr())
+	}
+	return exprs
+}
+
+func (g *ProgramGenerator) expr() ast.Expr {
+	if !g.enter() {
+		if g.chance(2) {
+			return g.basicLit()
+		}
+		return g.name()
+	}
+	defer g.leave()
+
+	binaryOps := []token.Token{token.ADD, token.SUB, token.MUL, token.QUO, token.REM, token.AND,
+		token.OR, token.XOR, token.SHL, token.SHR, token.AND_NOT, token.LAND, token.LOR,
+		token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ}
+	unaryOps := []token.Token{token.ADD, token.SUB, token.NOT, token.XOR, token.AND, token.ARROW}
+
+	switch g.rnd.Intn(16) {
+	case 0:
+		return &ast.BinaryExpr{X: g.expr(), Op: binaryOps[g.rnd.Intn(len(binaryOps))], Y: g.expr()}
+	case 1:
+		return &ast.UnaryExpr{Op: unaryOps[g.rnd.Intn(len(unaryOps))], X: g.name()}
+	case 2:
+		return &ast.ParenExpr{X: g.expr()}
+	case 3:
+		call := &ast.CallExpr{Fun: g.name(), Args: g.exprs(3)}
+		if len(call.Args) > 0 && g.chance(4) {
+			call.Ellipsis = 1
+		}
+		return call
+	case 4:
+		return &ast.SelectorExpr{X: g.name(), Sel: g.name()}
+	case 5:
+		return &ast.IndexExpr{X: g.name(), Index: g.expr()}
+	case 6:
+		s := &ast.SliceExpr{X: g.name()}
+		if g.chance(2) {
+			s.Low = g.expr()
+		}
+		if g.chance(2) {
+			s.High = g.expr()
+		}
+		if s.High != nil && g.chance(2) {
+			s.Max = g.expr()
+			s.Slice3 = true
+		}
+		return s
+	case 7:
+		return &ast.TypeAssertExpr{X: g.name(), Type: g.typeExpr()}
+	case 8:
+		return &ast.StarExpr{X: g.name()}
+	case 9:
+		if g.noCompositeLit {
+			return g.name()
+		}
+		lit := &ast.CompositeLit{Type: g.name()}
+		if g.chance(2) {
+			lit.Type = &ast.MapType{Key: ast.NewIdent("string"), Value: ast.NewIdent("int")}
+			count := g.rnd.Intn(3)
+			for i := 0; i < count; i++ {
+				lit.Elts = append(lit.Elts, &ast.KeyValueExpr{Key: g.stringLit(g.name().Name), Value: g.expr()})
+			}
+		} else {
+			lit.Elts = g.exprs(2)
+		}
+		return lit
+	case 10:
+		return &ast.FuncLit{Type: g.funcType(), Body: g.blockStmt()}
+	case 11, 12:
+		return g.basicLit()
+	}
+	return g.name()
+}
+
+func (g *ProgramGenerator) headerExpr() ast.Expr {
+	old := g.noCompositeLit
+	g.noCompositeLit = true
+	defer func() { g.noCompositeLit = old }()
+	return g.expr()
+}
+
+func (g *ProgramGenerator) headerStmt() ast.Stmt {
+	old := g.noCompositeLit
+	g.noCompositeLit = true
+	defer func() { g.noCompositeLit = old }()
+	return g.simpleStmt()
+}
+
+func (g *ProgramGenerator) blockStmt() *ast.BlockStmt {
+	b := &ast.BlockStmt{}
+	if !g.enter() {
+		return b
+	}
+	defer g.leave()
+
+	count := g.rnd.Intn(4)
+	for i := 0; i < count; i++ {
+		b.List = append(b.List, g.stmt())
+	}
+	return b
+}
+
+func (g *ProgramGenerator) simpleStmt() ast.Stmt {
+	switch g.rnd.Intn(4) {
+	case 0:
+		return &ast.IncDecStmt{X: g.name(), Tok: []token.Token{token.INC, token.DEC}[g.rnd.Intn(2)]}
+	case 1:
+		return &ast.ExprStmt{X: &ast.CallExpr{Fun: g.name(), Args: g.exprs(2)}}
+	case 2:
+		return &ast.SendStmt{Chan: g.name(), Value: g.expr()}
+	}
+	tok := []token.Token{token.ASSIGN, token.DEFINE, token.ADD_ASSIGN, token.SHL_ASSIGN}[g.rnd.Intn(4)]
+	lhs := []ast.Expr{g.name()}
+	if tok == token.ASSIGN || tok == token.DEFINE {
+		lhs = append(lhs, g.name())
+		return &ast.AssignStmt{Lhs: lhs, Tok: tok, Rhs: []ast.Expr{g.expr(), g.expr()}}
+	}
+	return &ast.AssignStmt{Lhs: lhs, Tok: tok, Rhs: []ast.Expr{g.expr()}}
+}
+
+func (g *ProgramGenerator) stmt() ast.Stmt {
+	switch g.rnd.Intn(16) {
+	case 0:
+		return &ast.ReturnStmt{Results: g.exprs(2)}
+	case 1:
+		s := &ast.IfStmt{Cond: g.headerExpr(), Body: g.blockStmt()}
+		if g.chance(3) {
+			s.Init = g.headerStmt()
+		}
+		switch g.rnd.Intn(3) {
+		case 0:
+			s.Else = g.blockStmt()
+		case 1:
+			s.Else = &ast.IfStmt{Cond: g.headerExpr(), Body: g.blockStmt()}
+		}
+		return s
+	case 2:
+		s := &ast.ForStmt{Body: g.blockStmt()}
+		if g.chance(2) {
+			s.Init = &ast.AssignStmt{Lhs: []ast.Expr{g.name()}, Tok: token.DEFINE, Rhs: []ast.Expr{g.intLit()}}
+			s.Cond = g.headerExpr()
+			s.Post = &ast.IncDecStmt{X: g.name(), Tok: token.INC}
+		} else if g.chance(2) {
+			s.Cond = g.headerExpr()
+		}
+		return s
+	case 3:
+		s := &ast.RangeStmt{X: g.headerExpr(), Body: g.blockStmt()}
+		if g.chance(3) {
+			s.Key = g.name()
+			s.Tok = token.DEFINE
+			if g.chance(2) {
+				s.Value = g.name()
+			}
+		}
+		return s
+	case 4:
+		s := &ast.SwitchStmt{Body: &ast.BlockStmt{}}
+		if g.chance(2) {
+			s.Tag = g.headerExpr()
+		}
+		if g.chance(3) {
+			s.Init = g.headerStmt()
+		}
+		count := g.rnd.Intn(3)
+		for i := 0; i < count; i++ {
+			s.Body.List = append(s.Body.List, &ast.CaseClause{List: []ast.Expr{g.headerExpr()}, Body: g.blockStmt().List})
+		}
+		if g.chance(2) {
+			s.Body.List = append(s.Body.List, &ast.CaseClause{Body: g.blockStmt().List})
+		}
+		return s
+	case 5:
+		assign := &ast.AssignStmt{
+			Lhs: []ast.Expr{g.name()},
+			Tok: token.DEFINE,
+			Rhs: []ast.Expr{&ast.TypeAssertExpr{X: g.name()}},
+		}
+		s := &ast.TypeSwitchStmt{Assign: assign, Body: &ast.BlockStmt{}}
+		if g.chance(2) {
+			s.Assign = &ast.ExprStmt{X: &ast.TypeAssertExpr{X: g.name()}}
+		}
+		s.Body.List = append(s.Body.List, &ast.CaseClause{List: []ast.Expr{g.typeExpr(), ast.NewIdent("nil")}})
+		if g.chance(2) {
+			s.Body.List = append(s.Body.List, &ast.CaseClause{Body: g.blockStmt().List})
+		}
+		return s
+	case 6:
+		s := &ast.SelectStmt{Body: &ast.BlockStmt{}}
+		s.Body.List = append(s.Body.List, &ast.CommClause{
+			Comm: &ast.AssignStmt{
+				Lhs: []ast.Expr{g.name()},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.UnaryExpr{Op: token.ARROW, X: g.name()}},
+			},
+			Body: g.blockStmt().List,
+		})
+		s.Body.List = append(s.Body.List, &ast.CommClause{Comm: &ast.SendStmt{Chan: g.name(), Value: g.intLit()}})
+		if g.chance(2) {
+			s.Body.List = append(s.Body.List, &ast.CommClause{Body: g.blockStmt().List})
+		}
+		return s
+	case 7:
+		return &ast.GoStmt{Call: &ast.CallExpr{Fun: g.name(), Args: g.exprs(2)}}
+	case 8:
+		return &ast.DeferStmt{Call: &ast.CallExpr{Fun: g.name()}}
+	case 9:
+		label := g.name()
+		body := g.blockStmt()
+		body.List = append(body.List, &ast.BranchStmt{Tok: []token.Token{token.BREAK, token.CONTINUE}[g.rnd.Intn(2)], Label: label})
+		return &ast.LabeledStmt{Label: label, Stmt: &ast.ForStmt{Body: body}}
+	case 10:
+		return g.blockStmt()
+	case 11:
+		return &ast.DeclStmt{Decl: g.valueDecl(token.VAR)}
+	}
+	return g.simpleStmt()
+}
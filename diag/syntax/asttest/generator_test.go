@@ -0,0 +1,23 @@
+package asttest_test
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateProgram(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		src := asttest.GenerateProgram(seed)
+		_, err := parser.ParseFile(token.NewFileSet(), "a.go", src, parser.ParseComments)
+		assert.NoError(t, err, "seed %d:\n%s", seed, src)
+	}
+}
+
+func TestGenerateProgramIsDeterministic(t *testing.T) {
+	assert.Equal(t, asttest.GenerateProgram(42), asttest.GenerateProgram(42))
+	assert.NotEqual(t, asttest.GenerateProgram(1), asttest.GenerateProgram(2))
+}
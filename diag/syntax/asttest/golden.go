@@ -0,0 +1,153 @@
+package asttest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/a6cexz/goanalyzer/diag/text/helpers"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// GoldenExt is the extension of golden files
+const GoldenExt = ".golden"
+
+// GetGoldenPath returns path of the golden file for the given fixture
+func GetGoldenPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + GoldenExt
+}
+
+// CheckGolden compares actual with the content of the golden file.
+// If tests are run with -update flag the golden file is rewritten instead.
+func CheckGolden(t *testing.T, goldenPath string, actual string) {
+	t.Helper()
+	if err := checkGolden(goldenPath, actual); err != nil {
+		t.Error(err)
+	}
+}
+
+func checkGolden(goldenPath string, actual string) error {
+	if *update {
+		if err := ioutil.WriteFile(goldenPath, []byte(actual), 0644); err != nil {
+			return fmt.Errorf("cannot write golden file: %v", err)
+		}
+		return nil
+	}
+
+	expected, err := ioutil.ReadFile(goldenPath)
+	if err != nil {
+		return fmt.Errorf("cannot read golden file (run tests with -update to create it): %v", err)
+	}
+
+	if string(expected) != actual {
+		return fmt.Errorf("%s does not match (run tests with -update to rewrite it)\n--- expected\n%s\n--- actual\n%s",
+			goldenPath, string(expected), actual)
+	}
+	return nil
+}
+
+// GetGoldenFixtures returns paths of the .go fixtures in the directory
+func GetGoldenFixtures(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".go" {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, f.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// RunGolden runs golden test for each .go fixture in the directory. Fixture marks the node with [|...|]
+// span or $$ caret, the innermost ast node enclosing the marked span is used; without markers the whole
+// file is used. Printed syntax tree of the node is compared with the .golden file next to the fixture.
+func RunGolden(t *testing.T, dir string) {
+	t.Helper()
+	paths, err := GetGoldenFixtures(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatalf("no .go fixtures found in %s", dir)
+	}
+
+	for _, path := range paths {
+		path := path
+		name := strings.TrimSuffix(filepath.Base(path), ".go")
+		t.Run(name, func(t *testing.T) {
+			runGoldenFixture(t, path)
+		})
+	}
+}
+
+func runGoldenFixture(t *testing.T, path string) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node, err := getGoldenNode(path, string(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	syntax.PrintTo(&buffer, syntax.FromAstNode(node))
+	CheckGolden(t, GetGoldenPath(path), buffer.String())
+}
+
+func getGoldenNode(path string, src string) (ast.Node, error) {
+	src, m, err := helpers.ParseMarkup(src)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Base(path), src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	span, ok := getMarkedSpan(m)
+	if !ok {
+		return file, nil
+	}
+
+	tokFile := fset.File(file.Pos())
+	if tokFile == nil {
+		return nil, fmt.Errorf("%s: file is not found in the file set", path)
+	}
+	start := tokFile.Pos(span.Start())
+	end := tokFile.Pos(span.End())
+	nodes, _ := astutil.PathEnclosingInterval(file, start, end)
+	return nodes[0], nil
+}
+
+func getMarkedSpan(m map[string][]text.TextSpan) (text.TextSpan, bool) {
+	if spans := m[helpers.AnonymousName]; len(spans) > 0 {
+		return spans[0], true
+	}
+	if spans := m[helpers.CaretName]; len(spans) > 0 {
+		return spans[0], true
+	}
+	return text.TextSpan{}, false
+}
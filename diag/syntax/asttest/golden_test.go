@@ -0,0 +1,19 @@
+package asttest_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunGolden(t *testing.T) {
+	asttest.RunGolden(t, "testdata/golden")
+}
+
+func TestGetGoldenFixtures(t *testing.T) {
+	paths, err := asttest.GetGoldenFixtures("testdata/golden")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testdata/golden/file.go", "testdata/golden/ident.go"}, paths)
+	assert.Equal(t, "testdata/golden/file.golden", asttest.GetGoldenPath(paths[0]))
+}
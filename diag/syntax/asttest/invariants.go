@@ -0,0 +1,185 @@
+package asttest
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"reflect"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+)
+
+// CheckInvariants checks invariants of the document syntax tree:
+// parent links are consistent, every element lies inside its parent ast node span, children are ordered
+// and do not overlap, token texts match the source and the source round trips through the tree tokens,
+// i.e. tokens follow each other in the source order and text between them contains only comments
+// and semicolons inserted at line ends.
+func CheckInvariants(doc *syntax.Document) []error {
+	c := &invariantChecker{doc: doc, src: doc.Text.String()}
+	c.checkNode(doc.Root)
+	c.checkRoundTrip()
+	return c.errors
+}
+
+// CheckConstructionPaths checks that syntax.FromAstNode and syntax.NewElementFromAst build the same tree
+// for every ast node of the document which subtree is supported by syntax.NewElementFromAst
+func CheckConstructionPaths(doc *syntax.Document) []error {
+	errors := []error{}
+	ast.Inspect(doc.AstFile, func(n ast.Node) bool {
+		if n == nil || !isTypedSubtree(n) {
+			return true
+		}
+
+		var generic, typed bytes.Buffer
+		syntax.PrintTo(&generic, syntax.FromAstNode(n))
+		syntax.PrintTo(&typed, syntax.NewElementFromAst(n))
+		if generic.String() != typed.String() {
+			errors = append(errors, fmt.Errorf("%s: construction paths disagree for %T\n--- FromAstNode\n%s\n--- NewElementFromAst\n%s",
+				formatAstPos(doc, n.Pos()), n, generic.String(), typed.String()))
+			return false
+		}
+		return true
+	})
+	return errors
+}
+
+func isTypedSubtree(root ast.Node) bool {
+	supported := true
+	ast.Inspect(root, func(n ast.Node) bool {
+		if n == nil || !supported {
+			return false
+		}
+		elmt := syntax.NewElementFromAst(n)
+		if elmt == nil || reflect.ValueOf(elmt).IsNil() {
+			supported = false
+		}
+		return supported
+	})
+	return supported
+}
+
+func formatAstPos(doc *syntax.Document, pos token.Pos) string {
+	return doc.File.Position(pos).String()
+}
+
+type invariantChecker struct {
+	doc    *syntax.Document
+	src    string
+	tokens []syntax.Token
+	errors []error
+}
+
+func (c *invariantChecker) errorf(pos token.Pos, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	c.errors = append(c.errors, fmt.Errorf("%s: %s", formatAstPos(c.doc, pos), msg))
+}
+
+func (c *invariantChecker) checkNode(node syntax.Node) {
+	astNode := node.GetAstNode()
+	astPos, astEnd := astNode.Pos(), astNode.End()
+	if _, ok := astNode.(*ast.File); ok {
+		// the file contains all the source, e.g. semicolon after the last declaration
+		astPos, astEnd = c.doc.GetPos(0), c.doc.GetPos(len(c.src))
+	}
+	var prev syntax.Element
+	for _, elmt := range node.GetElements() {
+		if elmt.GetParent() != node {
+			c.errorf(elmt.GetPos(), "%s has wrong parent", describeElement(elmt))
+		}
+
+		if elmt.GetPos().IsValid() {
+			if pos, end, ok := getAstSpan(elmt); ok && (pos < astPos || end > astEnd) {
+				c.errorf(elmt.GetPos(), "%s is outside of its parent %T", describeElement(elmt), astNode)
+			}
+
+			if prev != nil && prev.GetEnd() > elmt.GetPos() {
+				c.errorf(elmt.GetPos(), "%s overlaps with previous %s", describeElement(elmt), describeElement(prev))
+			}
+			prev = elmt
+		}
+
+		if syntax.IsToken(elmt) {
+			c.checkToken(elmt.(syntax.Token))
+		} else {
+			c.checkNode(elmt.(syntax.Node))
+		}
+	}
+}
+
+func (c *invariantChecker) checkToken(tok syntax.Token) {
+	if !tok.GetPos().IsValid() {
+		c.errorf(tok.GetPos(), "%s has no position", describeElement(tok))
+		return
+	}
+
+	span := c.doc.GetSpan(tok)
+	if actual := c.src[span.Start():span.End()]; actual != tok.GetText() {
+		c.errorf(tok.GetPos(), "%s text does not match source %q", describeElement(tok), actual)
+	}
+	c.tokens = append(c.tokens, tok)
+}
+
+func (c *invariantChecker) checkRoundTrip() {
+	pos := 0
+	for _, tok := range c.tokens {
+		span := c.doc.GetSpan(tok)
+		if span.Start() < pos {
+			c.errorf(tok.GetPos(), "%s is out of the source order or duplicated", describeElement(tok))
+			continue
+		}
+		c.checkGap(pos, span.Start())
+		pos = span.End()
+	}
+	c.checkGap(pos, len(c.src))
+}
+
+func (c *invariantChecker) checkGap(start, end int) {
+	if start >= end {
+		return
+	}
+
+	gap := c.src[start:end]
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(gap))
+	s.Init(file, []byte(gap), func(token.Position, string) {}, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			return
+		}
+		// comments and semicolons inserted at line ends are trivia
+		if tok != token.COMMENT && (tok != token.SEMICOLON || lit == ";") {
+			if lit == "" {
+				lit = tok.String()
+			}
+			c.errorf(c.doc.GetPos(start+file.Offset(pos)), "source token %q is missing in the syntax tree", lit)
+			return
+		}
+	}
+}
+
+// getAstSpan returns span of the token or span of the node ast, which unlike the node span
+// does not include doc and line comments. Comment groups are attached outside of their parent span
+// and are not checked.
+func getAstSpan(elmt syntax.Element) (token.Pos, token.Pos, bool) {
+	node, ok := elmt.(syntax.Node)
+	if !ok {
+		return elmt.GetPos(), elmt.GetEnd(), true
+	}
+	if _, ok := node.GetAstNode().(*ast.CommentGroup); ok {
+		return token.NoPos, token.NoPos, false
+	}
+	return node.GetAstNode().Pos(), node.GetAstNode().End(), true
+}
+
+func describeElement(elmt syntax.Element) string {
+	if tok, ok := elmt.(syntax.Token); ok {
+		return fmt.Sprintf("token %q", tok.GetText())
+	}
+	if node, ok := elmt.(syntax.Node); ok {
+		return fmt.Sprintf("node %T", node.GetAstNode())
+	}
+	return "element"
+}
@@ -0,0 +1,123 @@
+package asttest_test
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/stretchr/testify/assert"
+)
+
+const invariantsSrc = `// Package main doc
+package main
+
+import "fmt"
+
+type (
+	A = int
+	B struct {
+		a, b int ` + "`json:\"a\"`" + ` // line comment
+	}
+	I interface {
+		M(a ...int) (int, error)
+	}
+)
+
+func (b *B) f(ch <-chan int, out chan<- int) {
+	var x, y = 1, "y"
+	for k, v := range map[string]int{"a": 1} {
+		fmt.Println(k, v, x, y)
+	}
+	for range ch {
+	}
+	switch t := interface{}(b).(type) {
+	case *B:
+		_ = t
+	default:
+	}
+	select {
+	case v := <-ch:
+		out <- v
+	default:
+	}
+	if x > 0 {
+		fmt.Println(b.a, []int{1, 2}[:1])
+	} else {
+		fmt.Println(append([]int{}, []int{1}...))
+	}
+}
+`
+
+func addInvariantsDocument(t *testing.T) *syntax.Document {
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", invariantsSrc)
+	assert.NoError(t, err)
+	return doc
+}
+
+func TestCheckInvariants(t *testing.T) {
+	doc := addInvariantsDocument(t)
+	assert.Empty(t, asttest.CheckInvariants(doc))
+}
+
+func TestCheckInvariantsReportsMissingTokens(t *testing.T) {
+	doc := addInvariantsDocument(t)
+	doc.Root = syntax.FromAstNode(doc.AstFile.Decls[len(doc.AstFile.Decls)-1])
+
+	errors := asttest.CheckInvariants(doc)
+	if assert.NotEmpty(t, errors) {
+		assert.Equal(t, `a.go:2:1: source token "package" is missing in the syntax tree`, errors[0].Error())
+	}
+}
+
+func TestCheckInvariantsReportsUnrecordedTokens(t *testing.T) {
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", "package main\n\nvar x = 1\n")
+	assert.NoError(t, err)
+	assert.Empty(t, asttest.CheckInvariants(doc))
+
+	// tree built from the ast alone has no tokens whose positions go/ast does not record
+	doc.Root = syntax.FromAstNode(doc.AstFile)
+	errors := asttest.CheckInvariants(doc)
+	if assert.Len(t, errors, 1) {
+		assert.Equal(t, `a.go:3:7: source token "=" is missing in the syntax tree`, errors[0].Error())
+	}
+}
+
+func TestCheckInvariantsReportsMissingBrackets(t *testing.T) {
+	src := "package main\n\nvar x = a[0]\n"
+	r := syntax.NewDocumentRegistry()
+	doc, err := r.AddDocument("a.go", src)
+	assert.NoError(t, err)
+
+	ast.Inspect(doc.AstFile, func(n ast.Node) bool {
+		if index, ok := n.(*ast.IndexExpr); ok {
+			index.Lbrack = token.NoPos
+		}
+		return true
+	})
+	doc = syntax.NewDocument(r.GetFileSet(), "a.go", src, doc.AstFile)
+	errors := asttest.CheckInvariants(doc)
+	if assert.Len(t, errors, 2) {
+		assert.Equal(t, `-: token "[" has no position`, errors[0].Error())
+		assert.Equal(t, `a.go:3:10: source token "[" is missing in the syntax tree`, errors[1].Error())
+	}
+}
+
+func TestCheckInvariantsReportsDuplicatedTokens(t *testing.T) {
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", "package main\n\nfunc f() {}\n")
+	assert.NoError(t, err)
+
+	doc.AstFile.Decls = append(doc.AstFile.Decls, doc.AstFile.Decls[0])
+	doc.Root = syntax.FromAstNode(doc.AstFile)
+	errors := asttest.CheckInvariants(doc)
+	if assert.Len(t, errors, 7) {
+		assert.Equal(t, `a.go:3:1: node *ast.FuncDecl overlaps with previous node *ast.FuncDecl`, errors[0].Error())
+		assert.Equal(t, `a.go:3:1: token "func" is out of the source order or duplicated`, errors[1].Error())
+	}
+}
+
+func TestCheckConstructionPaths(t *testing.T) {
+	doc := addInvariantsDocument(t)
+	assert.Empty(t, asttest.CheckConstructionPaths(doc))
+}
@@ -0,0 +1,140 @@
+package asttest
+
+import (
+	"fmt"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/a6cexz/goanalyzer/diag/text/helpers"
+)
+
+// TestDocument represents parsed test fixture and its markup spans
+type TestDocument struct {
+	Document *syntax.Document
+	Spans    map[string][]text.TextSpan
+	// ParseError is the syntax error of the fixture, the document is built from the partial ast
+	ParseError error
+}
+
+// ParseTestDocument parses test fixture with $$, [|...|] and {|name:...|} markup
+func ParseTestDocument(src string) (*TestDocument, error) {
+	src, m, err := helpers.ParseMarkup(src)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := syntax.NewDocumentRegistry()
+	doc, err := registry.AddDocument("source.go", src)
+	if doc == nil {
+		return nil, err
+	}
+
+	r := &TestDocument{
+		Document:   doc,
+		Spans:      m,
+		ParseError: err,
+	}
+	return r, nil
+}
+
+// GetSpan returns the first span with the given name
+func (d *TestDocument) GetSpan(name string) (text.TextSpan, bool) {
+	spans := d.Spans[name]
+	if len(spans) == 0 {
+		return text.TextSpan{}, false
+	}
+	return spans[0], true
+}
+
+// GetMarkedSpan returns the first anonymous span or caret position
+func (d *TestDocument) GetMarkedSpan() (text.TextSpan, bool) {
+	if span, ok := d.GetSpan(helpers.AnonymousName); ok {
+		return span, true
+	}
+	return d.GetSpan(helpers.CaretName)
+}
+
+// FindNode returns the innermost node which contains the span
+func (d *TestDocument) FindNode(span text.TextSpan) syntax.Node {
+	return d.Document.FindNode(span)
+}
+
+// FindNodeOfKind returns the innermost node of the given kind which contains the span or nil.
+// The node is typed, e.g. *syntax.Ident or *syntax.BlockStmt, if typed nodes support its subtree.
+func (d *TestDocument) FindNodeOfKind(span text.TextSpan, kind syntaxkind.AstKind) syntax.Node {
+	for node := d.FindNode(span); node != nil; node = node.GetParent() {
+		if syntaxkind.GetAstKind(node.GetAstNode()) == kind {
+			return getTypedNode(node)
+		}
+	}
+	return nil
+}
+
+// getTypedNode returns typed node with the same parent for the node of the document tree
+// or the node itself if typed nodes do not support its subtree
+func getTypedNode(node syntax.Node) syntax.Node {
+	if !isTypedSubtree(node.GetAstNode()) {
+		return node
+	}
+	return syntax.NewElementFromAstAndParent(node.GetParent(), node.GetAstNode())
+}
+
+// GetNode returns the innermost node which contains the first span with the given name or nil
+func (d *TestDocument) GetNode(name string) syntax.Node {
+	span, ok := d.GetSpan(name)
+	if !ok {
+		return nil
+	}
+	return d.FindNode(span)
+}
+
+// GetNodes returns the innermost nodes which contain spans with the given name
+func (d *TestDocument) GetNodes(name string) []syntax.Node {
+	nodes := []syntax.Node{}
+	for _, span := range d.Spans[name] {
+		nodes = append(nodes, d.FindNode(span))
+	}
+	return nodes
+}
+
+// GetNodeOfKind returns the innermost node of the given kind which contains the first span with the given name or nil
+func (d *TestDocument) GetNodeOfKind(name string, kind syntaxkind.AstKind) syntax.Node {
+	span, ok := d.GetSpan(name)
+	if !ok {
+		return nil
+	}
+	return d.FindNodeOfKind(span, kind)
+}
+
+// GetTestNode returns the innermost node which contains the span marked with [|...|] or $$.
+// The node is typed, e.g. *syntax.Ident or *syntax.BlockStmt, if typed nodes support its subtree.
+func GetTestNode(src string) (syntax.Node, error) {
+	return GetTestNodeOfKind(src, syntaxkind.AstNone)
+}
+
+// GetTestNodeOfKind returns the innermost node of the given kind which contains the span marked with [|...|] or $$.
+// AstNone kind matches any node. The node is typed if typed nodes support its subtree.
+func GetTestNodeOfKind(src string, kind syntaxkind.AstKind) (syntax.Node, error) {
+	d, err := ParseTestDocument(src)
+	if err != nil {
+		return nil, err
+	}
+
+	span, ok := d.GetMarkedSpan()
+	if !ok {
+		return nil, fmt.Errorf("test source has no [|...|] or $$ markers")
+	}
+
+	var node syntax.Node
+	if kind == syntaxkind.AstNone {
+		node = d.FindNode(span)
+	} else {
+		node = d.FindNodeOfKind(span, kind)
+	}
+
+	if node == nil {
+		return nil, fmt.Errorf("no node of kind %v contains %v", kind, span)
+	}
+	return getTypedNode(node), nil
+}
@@ -0,0 +1,132 @@
+package asttest_test
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+	"github.com/stretchr/testify/assert"
+)
+
+func getNodeKind(node syntax.Node) syntaxkind.AstKind {
+	return syntaxkind.GetAstKind(node.GetAstNode())
+}
+
+func TestGetTestNode(t *testing.T) {
+	src := `package main
+func f() {
+	fmt.Println(a, [|b + c|])
+}
+`
+	node, err := asttest.GetTestNode(src)
+	assert.NoError(t, err)
+	assert.Equal(t, syntaxkind.AstBinaryExpr, getNodeKind(node))
+	assert.Equal(t, syntaxkind.AstCallExpr, getNodeKind(node.GetParent()))
+}
+
+func TestGetTestNodeOfKind(t *testing.T) {
+	src := `package main
+func f() {
+	g(fmt.Println(a, $$b))
+}
+`
+	node, err := asttest.GetTestNode(src)
+	assert.NoError(t, err)
+	assert.Equal(t, syntaxkind.AstIdent, getNodeKind(node))
+	assert.Equal(t, "b", node.GetAstNode().(*ast.Ident).Name)
+
+	node, err = asttest.GetTestNodeOfKind(src, syntaxkind.AstCallExpr)
+	assert.NoError(t, err)
+	assert.Equal(t, syntaxkind.AstSelectorExpr, getNodeKind(node.GetElements()[0].(syntax.Node)))
+
+	node, err = asttest.GetTestNodeOfKind(src, syntaxkind.AstFuncDecl)
+	assert.NoError(t, err)
+	assert.Equal(t, "f", node.GetAstNode().(*ast.FuncDecl).Name.Name)
+
+	_, err = asttest.GetTestNodeOfKind(src, syntaxkind.AstRangeStmt)
+	assert.Error(t, err)
+
+	_, err = asttest.GetTestNode("package main")
+	assert.Error(t, err)
+}
+
+func TestGetTestNodeCaretPrefersNextNode(t *testing.T) {
+	node, err := asttest.GetTestNode("package main\nvar x = a$$+b\n")
+	assert.NoError(t, err)
+	assert.Equal(t, syntaxkind.AstBinaryExpr, getNodeKind(node))
+
+	node, err = asttest.GetTestNode("package main\nvar x = a+$$b\n")
+	assert.NoError(t, err)
+	assert.Equal(t, syntaxkind.AstIdent, getNodeKind(node))
+	assert.Equal(t, "b", node.GetAstNode().(*ast.Ident).Name)
+}
+
+func TestTestDocumentSeveralMarkers(t *testing.T) {
+	src := `package main
+var {|v:x|}, {|v:y|} = {|lit:1|}, [|f()|]
+`
+	d, err := asttest.ParseTestDocument(src)
+	assert.NoError(t, err)
+	assert.NoError(t, d.ParseError)
+
+	nodes := d.GetNodes("v")
+	assert.Equal(t, 2, len(nodes))
+	assert.Equal(t, "x", nodes[0].GetAstNode().(*ast.Ident).Name)
+	assert.Equal(t, "y", nodes[1].GetAstNode().(*ast.Ident).Name)
+
+	assert.Equal(t, syntaxkind.AstBasicLit, getNodeKind(d.GetNode("lit")))
+	assert.Equal(t, syntaxkind.AstCallExpr, getNodeKind(d.GetNode("")))
+	assert.Equal(t, syntaxkind.AstValueSpec, getNodeKind(d.GetNodeOfKind("lit", syntaxkind.AstValueSpec)))
+	assert.Nil(t, d.GetNode("none"))
+}
+
+func TestTestDocumentParseError(t *testing.T) {
+	src := `package main
+func f() {
+	x := [|g()|]
+	y :=
+}
+`
+	d, err := asttest.ParseTestDocument(src)
+	assert.NoError(t, err)
+	assert.Error(t, d.ParseError)
+	assert.Equal(t, syntaxkind.AstCallExpr, getNodeKind(d.GetNode("")))
+}
+
+func TestGetTestNodeTyped(t *testing.T) {
+	src := `package main
+func f() int {
+	[|return x|]
+}
+`
+	node, err := asttest.GetTestNode("package main\nvar x = a+$$b\n")
+	assert.NoError(t, err)
+	ident, ok := node.(*syntax.Ident)
+	assert.True(t, ok)
+	assert.Equal(t, "b", ident.NameToken.GetText())
+	assert.Equal(t, syntaxkind.AstBinaryExpr, getNodeKind(ident.GetParent()))
+
+	node, err = asttest.GetTestNodeOfKind(src, syntaxkind.AstBlockStmt)
+	assert.NoError(t, err)
+	block, ok := node.(*syntax.BlockStmt)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(block.List))
+	assert.Equal(t, syntaxkind.AstFuncDecl, getNodeKind(block.GetParent()))
+
+	d, err := asttest.ParseTestDocument(src)
+	assert.NoError(t, err)
+	span, _ := d.GetMarkedSpan()
+	_, ok = d.FindNodeOfKind(span, syntaxkind.AstReturnStmt).(*syntax.ReturnStmt)
+	assert.True(t, ok)
+}
+
+func TestGetTestNodeUntyped(t *testing.T) {
+	// typed nodes do not support call expressions, the block is returned as the node of the document tree
+	node, err := asttest.GetTestNodeOfKind("package main\nfunc f() {\n\t$$g()\n}\n", syntaxkind.AstBlockStmt)
+	assert.NoError(t, err)
+	_, ok := node.(*syntax.BlockStmt)
+	assert.False(t, ok)
+	assert.Equal(t, syntaxkind.AstBlockStmt, getNodeKind(node))
+}
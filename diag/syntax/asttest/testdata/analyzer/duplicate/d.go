@@ -0,0 +1,6 @@
+package d
+
+func f() {
+	[|println|]("x") // want "avoid println" "avoid println"
+	print("y")
+}
@@ -0,0 +1,8 @@
+package b
+
+func f() {
+	println("x") // want "something else"
+	print([|"a"|])
+	[|print|]("b")
+	[|"c"|]
+}
@@ -0,0 +1,5 @@
+package c
+
+func f() {
+	print("x") // want "avoid print"
+}
@@ -0,0 +1,7 @@
+package a
+
+func f() {
+	[|println|]("x") // want "avoid println"
+	println("y") // want `avoid \w+`
+	print("z")
+}
@@ -0,0 +1,321 @@
+package syntax
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"sync"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// Document represents parsed source file and its syntax tree
+type Document struct {
+	Path    string
+	Text    *text.SourceText
+	File    *token.File
+	AstFile *ast.File
+	Root    Node
+	// Diagnostics are syntax errors of the document
+	Diagnostics []diag.Diagnostic
+}
+
+// FileLinePositionSpan represents line position span in the file
+type FileLinePositionSpan struct {
+	Path string
+	Span text.LinePositionSpan
+}
+
+// Location represents location of the syntax element in the document
+type Location struct {
+	Path     string
+	Span     text.TextSpan
+	LineSpan text.LinePositionSpan
+
+	// MappedPath and MappedLineSpan take //line directives into account
+	MappedPath     string
+	MappedLineSpan text.LinePositionSpan
+}
+
+// NewDocument creates document from the file which is already parsed with the file set,
+// src must be the source the file was parsed from
+func NewDocument(fset *token.FileSet, path string, src string, file *ast.File) *Document {
+	tokFile := fset.File(file.Pos())
+	root := FromAstNode(file)
+	if tokFile != nil {
+		loadUnrecordedTokens(root, tokFile, src)
+	}
+	return &Document{
+		Path:        path,
+		Text:        text.NewSourceText(src),
+		File:        tokFile,
+		AstFile:     file,
+		Root:        root,
+		Diagnostics: []diag.Diagnostic{},
+	}
+}
+
+// Contains checks if position belongs to the document
+func (d *Document) Contains(pos token.Pos) bool {
+	return pos.IsValid() && pos >= token.Pos(d.File.Base()) && pos <= token.Pos(d.File.Base()+d.File.Size())
+}
+
+// GetOffset returns offset of the position in the document text. Positions outside of the document are clamped.
+func (d *Document) GetOffset(pos token.Pos) int {
+	base := d.File.Base()
+	offset := int(pos) - base
+	if !pos.IsValid() || offset < 0 {
+		return 0
+	}
+	if offset > d.File.Size() {
+		return d.File.Size()
+	}
+	return offset
+}
+
+// GetPos returns position of the offset in the document text
+func (d *Document) GetPos(offset int) token.Pos {
+	return d.File.Pos(offset)
+}
+
+// GetSpan returns text span of the syntax element
+func (d *Document) GetSpan(elmt Element) text.TextSpan {
+	start := d.GetOffset(elmt.GetPos())
+	end := d.GetOffset(elmt.GetEnd())
+	if end < start {
+		end = start
+	}
+	return text.NewTextSpanFromBounds(start, end)
+}
+
+// GetLineSpan returns line position span of the text span
+func (d *Document) GetLineSpan(span text.TextSpan, unit text.CharacterUnit) FileLinePositionSpan {
+	return FileLinePositionSpan{
+		Path: d.Path,
+		Span: d.Text.GetLinePositionSpan(span, unit),
+	}
+}
+
+// GetMappedLineSpan returns line position span of the text span taking //line directives into account
+func (d *Document) GetMappedLineSpan(span text.TextSpan, unit text.CharacterUnit) FileLinePositionSpan {
+	path, start := d.getMappedLinePosition(span.Start(), unit)
+	_, end := d.getMappedLinePosition(span.End(), unit)
+	if end.CompareTo(start) < 0 {
+		end = start
+	}
+	return FileLinePositionSpan{
+		Path: path,
+		Span: text.NewLinePositionSpan(start, end),
+	}
+}
+
+// GetLocation returns location of the syntax element
+func (d *Document) GetLocation(elmt Element, unit text.CharacterUnit) Location {
+	span := d.GetSpan(elmt)
+	mapped := d.GetMappedLineSpan(span, unit)
+	return Location{
+		Path:           d.Path,
+		Span:           span,
+		LineSpan:       d.GetLineSpan(span, unit).Span,
+		MappedPath:     mapped.Path,
+		MappedLineSpan: mapped.Span,
+	}
+}
+
+// FindNode returns the innermost node which contains the span or nil if the span is outside of the root node.
+// For an empty span nodes which start at the position are preferred over nodes which end at it.
+func (d *Document) FindNode(span text.TextSpan) Node {
+	if d.Root == nil || !d.GetSpan(d.Root).ContainsSpan(span) {
+		return nil
+	}
+
+	node := d.Root
+	for {
+		child := d.findChildNode(node, span)
+		if child == nil {
+			return node
+		}
+		node = child
+	}
+}
+
+func (d *Document) findChildNode(node Node, span text.TextSpan) Node {
+	var candidate Node
+	for _, elmt := range node.GetElements() {
+		child, ok := elmt.(Node)
+		if ok && isNilNode2(child) {
+			continue
+		}
+
+		elmtSpan := d.GetSpan(elmt)
+		if !elmtSpan.ContainsSpan(span) {
+			continue
+		}
+
+		if !span.IsEmpty() || elmtSpan.ContainsPos(span.Start()) {
+			// token which starts at the position belongs to the node itself
+			return child
+		}
+
+		if ok && candidate == nil {
+			candidate = child
+		}
+	}
+	return candidate
+}
+
+func (d *Document) getMappedLinePosition(offset int, unit text.CharacterUnit) (string, text.LinePosition) {
+	pos := d.Text.GetLinePosition(offset, unit)
+	if offset > d.File.Size() {
+		return d.Path, pos
+	}
+
+	p := d.File.Pos(offset)
+	physical := d.File.PositionFor(p, false)
+	adjusted := d.File.PositionFor(p, true)
+	if adjusted.Line < 1 {
+		return d.Path, pos
+	}
+
+	character := pos.Character
+	if adjusted.Column > 0 && adjusted.Column != physical.Column {
+		// mapped columns are in bytes, the text after the start of the mapping is measured in the unit
+		start := d.getMappingStart(offset, physical, adjusted)
+		startColumn := adjusted.Column - (offset - start)
+		character = startColumn - 1 + pos.Character - d.Text.GetLinePosition(start, unit).Character
+		if character < 0 {
+			character = 0
+		}
+	}
+	return adjusted.Filename, text.NewLinePosition(adjusted.Line-1, character)
+}
+
+// getMappingStart returns offset of the line where the //line directive mapping of the offset starts
+func (d *Document) getMappingStart(offset int, physical token.Position, adjusted token.Position) int {
+	delta := adjusted.Column - physical.Column
+	start := offset
+	for lineStart := offset - (physical.Column - 1); start > lineStart; start-- {
+		p := d.File.Pos(start - 1)
+		prevPhysical := d.File.PositionFor(p, false)
+		prevAdjusted := d.File.PositionFor(p, true)
+		if prevAdjusted.Filename != adjusted.Filename || prevAdjusted.Line != adjusted.Line ||
+			prevAdjusted.Column-prevPhysical.Column != delta {
+			break
+		}
+	}
+	return start
+}
+
+// DocumentResolver returns document by its path, *DocumentRegistry implements it
+type DocumentResolver interface {
+	GetDocument(path string) *Document
+}
+
+// DocumentRegistry owns the file set and maps positions and syntax elements to documents
+type DocumentRegistry struct {
+	mu    sync.RWMutex
+	fset  *token.FileSet
+	paths map[string]*Document
+	files map[*token.File]*Document
+}
+
+// NewDocumentRegistry creates new document registry
+func NewDocumentRegistry() *DocumentRegistry {
+	return &DocumentRegistry{
+		fset:  token.NewFileSet(),
+		paths: map[string]*Document{},
+		files: map[*token.File]*Document{},
+	}
+}
+
+// GetFileSet returns file set of the registry
+func (r *DocumentRegistry) GetFileSet() *token.FileSet {
+	return r.fset
+}
+
+// AddDocument parses the source and adds new document to the registry. Document with the same path is replaced.
+// If source has syntax errors the document is built from the partial ast and the error is returned as well.
+func (r *DocumentRegistry) AddDocument(path string, src string) (*Document, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	base := r.fset.Base()
+	file, root, diags, err := parseFile(r.fset, path, src)
+	doc := &Document{
+		Path:        path,
+		Text:        text.NewSourceText(src),
+		File:        r.fset.File(token.Pos(base)),
+		AstFile:     file,
+		Root:        root,
+		Diagnostics: diags,
+	}
+
+	if old, ok := r.paths[path]; ok {
+		delete(r.files, old.File)
+		r.fset.RemoveFile(old.File)
+	}
+	r.paths[path] = doc
+	r.files[doc.File] = doc
+	return doc, err
+}
+
+// GetDocument returns document with the given path or nil
+func (r *DocumentRegistry) GetDocument(path string) *Document {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.paths[path]
+}
+
+// GetDocuments returns all documents sorted by path
+func (r *DocumentRegistry) GetDocuments() []*Document {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	docs := []*Document{}
+	for _, doc := range r.paths {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		return docs[i].Path < docs[j].Path
+	})
+	return docs
+}
+
+// GetDocumentFromPos returns document which contains the position or nil
+func (r *DocumentRegistry) GetDocumentFromPos(pos token.Pos) *Document {
+	if !pos.IsValid() {
+		return nil
+	}
+
+	file := r.fset.File(pos)
+	if file == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.files[file]
+}
+
+// GetSourceText returns text of the document with the given path
+func (r *DocumentRegistry) GetSourceText(path string) (*text.SourceText, bool) {
+	doc := r.GetDocument(path)
+	if doc == nil {
+		return nil, false
+	}
+	return doc.Text, true
+}
+
+// GetDocumentFromElement returns document which contains the syntax element or nil
+func (r *DocumentRegistry) GetDocumentFromElement(elmt Element) *Document {
+	return r.GetDocumentFromPos(elmt.GetPos())
+}
+
+// GetLocation returns location of the syntax element, ok is false if element does not belong to any document
+func (r *DocumentRegistry) GetLocation(elmt Element, unit text.CharacterUnit) (loc Location, ok bool) {
+	doc := r.GetDocumentFromElement(elmt)
+	if doc == nil {
+		return Location{}, false
+	}
+	return doc.GetLocation(elmt, unit), true
+}
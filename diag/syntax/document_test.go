@@ -0,0 +1,162 @@
+package syntax_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func findTestNode(root syntax.Node, predicate func(ast.Node) bool) syntax.Node {
+	if predicate(root.GetAstNode()) {
+		return root
+	}
+	for _, elmt := range root.GetElements() {
+		if node, ok := elmt.(syntax.Node); ok {
+			if r := findTestNode(node, predicate); r != nil {
+				return r
+			}
+		}
+	}
+	return nil
+}
+
+func findTestIdent(root syntax.Node, name string) syntax.Node {
+	return findTestNode(root, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		return ok && ident.Name == name
+	})
+}
+
+func TestDocumentRegistry(t *testing.T) {
+	r := syntax.NewDocumentRegistry()
+	a, err := r.AddDocument("a.go", "package a\n\nvar x = 1\n")
+	assert.NoError(t, err)
+	b, err := r.AddDocument("b.go", "package a\n\n// Doc\nfunc f() {\n\ty := \"日本\"\n}\n")
+	assert.NoError(t, err)
+
+	assert.True(t, a == r.GetDocument("a.go"))
+	assert.Nil(t, r.GetDocument("c.go"))
+	assert.Equal(t, []*syntax.Document{a, b}, r.GetDocuments())
+
+	x := findTestIdent(a.Root, "x")
+	assert.True(t, a == r.GetDocumentFromElement(x))
+	assert.Equal(t, text.NewTextSpan(15, 1), a.GetSpan(x))
+
+	lit := findTestNode(b.Root, func(n ast.Node) bool {
+		_, ok := n.(*ast.BasicLit)
+		return ok
+	})
+	assert.True(t, b == r.GetDocumentFromElement(lit))
+
+	loc, ok := r.GetLocation(lit, text.CharacterUnitUTF16)
+	assert.True(t, ok)
+	assert.Equal(t, "b.go", loc.Path)
+	assert.Equal(t, `"日本"`, b.Text.GetSubText(loc.Span))
+	assert.Equal(t, "(4:6)-(4:10)", loc.LineSpan.String())
+	assert.Equal(t, "b.go", loc.MappedPath)
+	assert.Equal(t, loc.LineSpan, loc.MappedLineSpan)
+
+	loc, _ = r.GetLocation(lit, text.CharacterUnitByte)
+	assert.Equal(t, "(4:6)-(4:14)", loc.LineSpan.String())
+
+	decl := findTestNode(b.Root, func(n ast.Node) bool {
+		_, ok := n.(*ast.FuncDecl)
+		return ok
+	})
+	assert.Equal(t, "// Doc\nfunc f() {\n\ty := \"日本\"\n}", b.Text.GetSubText(b.GetSpan(decl)))
+
+	_, ok = r.GetLocation(syntax.FromAstNode(&ast.Ident{Name: "z"}), text.CharacterUnitByte)
+	assert.False(t, ok)
+}
+
+func TestDocumentRegistryReplaceDocument(t *testing.T) {
+	r := syntax.NewDocumentRegistry()
+	a1, _ := r.AddDocument("a.go", "package a\n")
+	a2, _ := r.AddDocument("a.go", "package b\n")
+	assert.True(t, a2 == r.GetDocument("a.go"))
+	assert.Nil(t, r.GetDocumentFromElement(a1.Root))
+	assert.True(t, a2 == r.GetDocumentFromElement(a2.Root))
+	assert.Nil(t, r.GetFileSet().File(a1.Root.GetPos()))
+}
+
+func TestDocumentRegistryParseError(t *testing.T) {
+	r := syntax.NewDocumentRegistry()
+	doc, err := r.AddDocument("a.go", "package a\n\nvar x = \n")
+	assert.Error(t, err)
+	assert.NotNil(t, doc)
+	assert.NotNil(t, doc.Root)
+	assert.True(t, doc == r.GetDocument("a.go"))
+	if assert.Len(t, doc.Diagnostics, 1) {
+		assert.Equal(t, "a.go[20..20): error GA0001: expected operand, found 'EOF'", doc.Diagnostics[0].String())
+	}
+}
+
+func TestNewDocument(t *testing.T) {
+	src := "// Doc\npackage a\n\nvar x = 1\n"
+	fset := token.NewFileSet()
+	fset.AddFile("other.go", -1, 10)
+	file, err := parser.ParseFile(fset, "a.go", src, parser.ParseComments)
+	assert.NoError(t, err)
+
+	doc := syntax.NewDocument(fset, "a.go", src, file)
+	assert.Equal(t, "a.go", doc.File.Name())
+	assert.True(t, doc.Root.GetAstNode() == file)
+	assert.Empty(t, doc.Diagnostics)
+
+	x := findTestIdent(doc.Root, "x")
+	assert.Equal(t, text.NewTextSpan(22, 1), doc.GetSpan(x))
+}
+
+func TestDocumentFindNode(t *testing.T) {
+	src := "package a\n\nfunc f() {\n\tprintln(1)\n}\n"
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", src)
+	assert.NoError(t, err)
+
+	node := doc.FindNode(text.NewTextSpan(31, 1))
+	if assert.NotNil(t, node) {
+		assert.IsType(t, &ast.BasicLit{}, node.GetAstNode())
+	}
+	assert.True(t, doc.FindNode(text.NewTextSpan(0, 0)) == doc.Root)
+	assert.Nil(t, doc.FindNode(text.NewTextSpan(500, 0)))
+	assert.Nil(t, doc.FindNode(text.NewTextSpan(30, 100)))
+}
+
+func TestDocumentLineDirective(t *testing.T) {
+	src := "package a\n\n//line gen.y:10:5\nvar x = 1\nvar y = 2\n"
+	r := syntax.NewDocumentRegistry()
+	doc, err := r.AddDocument("a.go", src)
+	assert.NoError(t, err)
+
+	x := findTestIdent(doc.Root, "x")
+	loc := doc.GetLocation(x, text.CharacterUnitByte)
+	assert.Equal(t, "(3:4)-(3:5)", loc.LineSpan.String())
+	assert.Equal(t, "gen.y", loc.MappedPath)
+	assert.Equal(t, "(9:8)-(9:9)", loc.MappedLineSpan.String())
+
+	y := findTestIdent(doc.Root, "y")
+	loc = doc.GetLocation(y, text.CharacterUnitByte)
+	assert.Equal(t, "(4:4)-(4:5)", loc.LineSpan.String())
+	assert.Equal(t, "gen.y", loc.MappedPath)
+	assert.Equal(t, "(10:4)-(10:5)", loc.MappedLineSpan.String())
+}
+
+func TestDocumentLineDirectiveColumn(t *testing.T) {
+	src := "package a\n\nvar s = \"日本\" /*line gen.y:10:5*/ + \"語\" + x\n"
+	r := syntax.NewDocumentRegistry()
+	doc, err := r.AddDocument("a.go", src)
+	assert.NoError(t, err)
+
+	x := findTestIdent(doc.Root, "x")
+	loc := doc.GetLocation(x, text.CharacterUnitByte)
+	assert.Equal(t, "(2:47)-(2:48)", loc.LineSpan.String())
+	assert.Equal(t, "(9:15)-(9:16)", loc.MappedLineSpan.String())
+
+	loc = doc.GetLocation(x, text.CharacterUnitUTF16)
+	assert.Equal(t, "(2:41)-(2:42)", loc.LineSpan.String())
+	assert.Equal(t, "(9:13)-(9:14)", loc.MappedLineSpan.String())
+}
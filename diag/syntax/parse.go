@@ -0,0 +1,245 @@
+package syntax
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"reflect"
+	"sort"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// SyntaxErrorID is the id of diagnostics reported for syntax errors
+const SyntaxErrorID = "GA0001"
+
+// ParseFile parses the source and builds its syntax tree. The tree is built even if the source has syntax
+// errors: errors are returned as diagnostics and source skipped by the parser is kept as tokens of Bad* nodes.
+func ParseFile(fset *token.FileSet, path string, src string) (*ast.File, Node, []diag.Diagnostic) {
+	file, root, diags, _ := parseFile(fset, path, src)
+	return file, root, diags
+}
+
+func parseFile(fset *token.FileSet, path string, src string) (*ast.File, Node, []diag.Diagnostic, error) {
+	base := fset.Base()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if file == nil {
+		file = &ast.File{Name: ast.NewIdent("_")}
+	}
+
+	root := FromAstNode(file)
+	tokFile := fset.File(token.Pos(base))
+	if tokFile != nil {
+		if err != nil {
+			tokens := map[token.Pos]bool{}
+			collectTokenPositions(root, tokens)
+			loadBadNodes(root, tokFile, src, tokens)
+		}
+		loadUnrecordedTokens(root, tokFile, src)
+	}
+	return file, root, getSyntaxDiagnostics(path, src, err), err
+}
+
+func getSyntaxDiagnostics(path string, src string, err error) []diag.Diagnostic {
+	diags := []diag.Diagnostic{}
+	if err == nil {
+		return diags
+	}
+
+	list, ok := err.(scanner.ErrorList)
+	if !ok {
+		location := diag.NewLocation(path, text.NewTextSpan(0, 0))
+		return append(diags, diag.NewDiagnostic(SyntaxErrorID, diag.SeverityError, location, err.Error()))
+	}
+
+	for _, e := range list {
+		location := diag.NewLocation(path, getSyntaxErrorSpan(src, e.Pos.Offset))
+		diags = append(diags, diag.NewDiagnostic(SyntaxErrorID, diag.SeverityError, location, e.Msg))
+	}
+	return diags
+}
+
+// getSyntaxErrorSpan returns span of the token at the error offset or empty span if there is no token
+func getSyntaxErrorSpan(src string, offset int) text.TextSpan {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(src) {
+		offset = len(src)
+	}
+
+	length := 0
+	scanTokens(src[offset:], func(pos int, tok token.Token, lit string) bool {
+		if pos == 0 && tok != token.EOF {
+			length = len(getTokenText(tok, lit))
+		}
+		return false
+	})
+	return text.NewTextSpan(offset, length)
+}
+
+func collectTokenPositions(node Node, tokens map[token.Pos]bool) {
+	for _, elmt := range node.GetElements() {
+		if IsToken(elmt) {
+			tokens[elmt.GetPos()] = true
+		} else {
+			collectTokenPositions(elmt.(Node), tokens)
+		}
+	}
+}
+
+// loadBadNodes adds tokens of the source skipped by the parser to BadExpr, BadStmt and BadDecl nodes.
+// Range of the bad node may include tokens of other nodes, e.g. closing brace of the block, such tokens are skipped.
+func loadBadNodes(node Node, tokFile *token.File, src string, tokens map[token.Pos]bool) {
+	n, ok := node.(*nodeImpl)
+	if !ok {
+		return
+	}
+
+	var from, to token.Pos
+	switch b := n.AstNode.(type) {
+	case *ast.BadExpr:
+		from, to = b.From, b.To
+	case *ast.BadStmt:
+		from, to = b.From, b.To
+	case *ast.BadDecl:
+		from, to = b.From, b.To
+	default:
+		for _, elmt := range n.Elements {
+			if child, ok := elmt.(Node); ok {
+				loadBadNodes(child, tokFile, src, tokens)
+			}
+		}
+		return
+	}
+
+	start, end, ok := getFileOffsets(tokFile, from, to)
+	if !ok {
+		return
+	}
+
+	elmts := []Element{}
+	scanTokens(src[start:end], func(pos int, tok token.Token, lit string) bool {
+		if tok == token.EOF {
+			return false
+		}
+		// skip semicolons inserted by the scanner at line ends
+		p := tokFile.Pos(start + pos)
+		if !tokens[p] && (tok != token.SEMICOLON || lit == ";") {
+			elmts = appendToken(elmts, n, p, getTokenText(tok, lit), tok)
+		}
+		return true
+	})
+	n.Elements = elmts
+}
+
+// unrecordedTokens are tokens whose positions go/ast does not record and the nodes which contain them,
+// e.g. commas of lists, period of a selector, = of a value spec or else of an if statement
+var unrecordedTokens = map[token.Token][]ast.Node{
+	token.COMMA: {&ast.CallExpr{}, &ast.CompositeLit{}, &ast.Field{}, &ast.FieldList{}, &ast.AssignStmt{},
+		&ast.ValueSpec{}, &ast.ReturnStmt{}, &ast.CaseClause{}, &ast.RangeStmt{}},
+	token.SEMICOLON: {&ast.File{}, &ast.GenDecl{}, &ast.FieldList{}, &ast.BlockStmt{}, &ast.CaseClause{},
+		&ast.CommClause{}, &ast.IfStmt{}, &ast.SwitchStmt{}, &ast.TypeSwitchStmt{}, &ast.ForStmt{}},
+	token.PERIOD: {&ast.SelectorExpr{}, &ast.TypeAssertExpr{}},
+	token.COLON:  {&ast.SliceExpr{}},
+	token.ASSIGN: {&ast.ValueSpec{}},
+	token.LBRACK: {&ast.MapType{}},
+	token.RBRACK: {&ast.ArrayType{}, &ast.MapType{}},
+	token.ELSE:   {&ast.IfStmt{}},
+	token.RANGE:  {&ast.RangeStmt{}},
+	token.TYPE:   {&ast.TypeAssertExpr{}},
+	token.CHAN:   {&ast.ChanType{}},
+}
+
+// loadUnrecordedTokens adds tokens of the source whose positions go/ast does not record to the innermost node
+// which contains them. Tokens are added only to the nodes listed in unrecordedTokens, so tokens which the tree
+// should have built from the ast stay missing. Comments and semicolons inserted at line ends are not added.
+func loadUnrecordedTokens(root Node, tokFile *token.File, src string) {
+	tokens := map[token.Pos]bool{}
+	collectTokenPositions(root, tokens)
+	scanTokens(src, func(pos int, tok token.Token, lit string) bool {
+		if tok == token.EOF {
+			return false
+		}
+		p := tokFile.Pos(pos)
+		if tokens[p] || (tok == token.SEMICOLON && lit != ";") {
+			return true
+		}
+
+		text := getTokenText(tok, lit)
+		n, ok := findInnermostNode(root, p, p+token.Pos(len(text))).(*nodeImpl)
+		if ok && isUnrecordedToken(tok, n.AstNode) {
+			insertToken(n, tokenNode(n, p, text, tok))
+		}
+		return true
+	})
+}
+
+func isUnrecordedToken(tok token.Token, node ast.Node) bool {
+	for _, n := range unrecordedTokens[tok] {
+		if reflect.TypeOf(n) == reflect.TypeOf(node) {
+			return true
+		}
+	}
+	return false
+}
+
+// findInnermostNode returns the innermost node whose ast contains the range from pos to end,
+// the root is returned if no node contains it
+func findInnermostNode(node Node, pos, end token.Pos) Node {
+	for _, elmt := range node.GetElements() {
+		child, ok := elmt.(Node)
+		if !ok {
+			continue
+		}
+		if _, ok := child.GetAstNode().(*ast.CommentGroup); ok {
+			continue
+		}
+		if child.GetAstNode().Pos() <= pos && end <= child.GetAstNode().End() {
+			return findInnermostNode(child, pos, end)
+		}
+	}
+	return node
+}
+
+// insertToken inserts the token before the first element of the node which starts after it
+func insertToken(n *nodeImpl, tok Token) {
+	i := sort.Search(len(n.Elements), func(i int) bool {
+		return n.Elements[i].GetPos() > tok.GetPos()
+	})
+	n.Elements = append(n.Elements, nil)
+	copy(n.Elements[i+1:], n.Elements[i:])
+	n.Elements[i] = tok
+}
+
+func getFileOffsets(tokFile *token.File, from, to token.Pos) (int, int, bool) {
+	base := tokFile.Base()
+	start := int(from) - base
+	end := int(to) - base
+	if !from.IsValid() || !to.IsValid() || start < 0 || end > tokFile.Size() || end < start {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+func getTokenText(tok token.Token, lit string) string {
+	if lit != "" {
+		return lit
+	}
+	return tok.String()
+}
+
+// scanTokens scans tokens of the source and calls fn with offset of each token until fn returns false
+func scanTokens(src string, fn func(pos int, tok token.Token, lit string) bool) {
+	var s scanner.Scanner
+	file := token.NewFileSet().AddFile("", -1, len(src))
+	s.Init(file, []byte(src), func(token.Position, string) {}, 0)
+	for {
+		pos, tok, lit := s.Scan()
+		if !fn(file.Offset(pos), tok, lit) || tok == token.EOF {
+			return
+		}
+	}
+}
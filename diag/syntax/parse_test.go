@@ -0,0 +1,102 @@
+package syntax_test
+
+import (
+	"go/ast"
+	"go/token"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func getTokenTexts(node syntax.Node) []string {
+	texts := []string{}
+	for _, elmt := range node.GetElements() {
+		if tok, ok := elmt.(syntax.Token); ok {
+			texts = append(texts, tok.GetText())
+		}
+	}
+	return texts
+}
+
+func TestParseFile(t *testing.T) {
+	file, root, diags := syntax.ParseFile(token.NewFileSet(), "a.go", "package a\n\nvar x = 1\n")
+	assert.NotNil(t, file)
+	assert.True(t, root.GetAstNode() == file)
+	assert.Empty(t, diags)
+}
+
+func TestParseFileBadExpr(t *testing.T) {
+	file, root, diags := syntax.ParseFile(token.NewFileSet(), "a.go", "package a\n\nvar x = )\n")
+	assert.NotNil(t, file)
+	if assert.Len(t, diags, 1) {
+		d := diags[0]
+		assert.Equal(t, syntax.SyntaxErrorID, d.ID)
+		assert.Equal(t, diag.SeverityError, d.Severity)
+		assert.Equal(t, diag.NewLocation("a.go", text.NewTextSpan(19, 1)), d.Location)
+		assert.Equal(t, "expected operand, found ')'", d.GetMessage())
+	}
+
+	bad := findTestNode(root, func(n ast.Node) bool {
+		_, ok := n.(*ast.BadExpr)
+		return ok
+	})
+	if assert.NotNil(t, bad) {
+		assert.Equal(t, []string{")"}, getTokenTexts(bad))
+	}
+}
+
+func TestParseFileBadDecl(t *testing.T) {
+	_, root, diags := syntax.ParseFile(token.NewFileSet(), "a.go", "package a\n\n+ 1 \"s\"\nvar b = 1\n")
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "a.go[11..12): error GA0001: expected declaration, found '+'", diags[0].String())
+	}
+
+	bad := findTestNode(root, func(n ast.Node) bool {
+		_, ok := n.(*ast.BadDecl)
+		return ok
+	})
+	if assert.NotNil(t, bad) {
+		assert.Equal(t, []string{"+", "1", `"s"`}, getTokenTexts(bad))
+		assert.True(t, bad.GetParent() == root)
+	}
+	assert.NotNil(t, findTestIdent(root, "b"))
+}
+
+func TestParseFileBadStmtTokensAreNotDuplicated(t *testing.T) {
+	src := "package a\n\nfunc f() {\n\tif x {\n\t} else + 1 {}\n}\n"
+	_, root, diags := syntax.ParseFile(token.NewFileSet(), "a.go", src)
+	assert.NotEmpty(t, diags)
+
+	bad := findTestNode(root, func(n ast.Node) bool {
+		_, ok := n.(*ast.BadStmt)
+		return ok
+	})
+	if assert.NotNil(t, bad) {
+		assert.Empty(t, bad.GetElements())
+	}
+}
+
+func TestParseFileUnrecordedTokens(t *testing.T) {
+	src := "package a\n\nvar x, y = m[1:2], <-ch\n"
+	_, root, diags := syntax.ParseFile(token.NewFileSet(), "a.go", src)
+	assert.Empty(t, diags)
+
+	spec := findTestNode(root, func(n ast.Node) bool {
+		_, ok := n.(*ast.ValueSpec)
+		return ok
+	})
+	if assert.NotNil(t, spec) {
+		assert.Equal(t, []string{",", "=", ","}, getTokenTexts(spec))
+	}
+
+	slice := findTestNode(root, func(n ast.Node) bool {
+		_, ok := n.(*ast.SliceExpr)
+		return ok
+	})
+	if assert.NotNil(t, slice) {
+		assert.Equal(t, []string{"[", ":", "]"}, getTokenTexts(slice))
+	}
+}
@@ -0,0 +1,305 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+)
+
+// Error is the syntax error of the query
+type Error struct {
+	// Offset is the byte offset of the error in the query
+	Offset  int
+	Message string
+}
+
+// Error returns the error message with 1-based column of the error
+func (e *Error) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Offset+1, e.Message)
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenName
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+// queryToken is the token of the query, space tells if the token is preceded by whitespace
+type queryToken struct {
+	kind   tokenKind
+	text   string
+	offset int
+	space  bool
+}
+
+// punctuation of the query, longer operators go first
+var punctuation = []string{"!=", "<=", ">=", "~=", ">", "<", "=", ",", "[", "]", ".", "*"}
+
+func scan(src string) ([]queryToken, error) {
+	tokens := []queryToken{}
+	space := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			i++
+			continue
+		case isNameStart(c):
+			j := i + 1
+			for j < len(src) && (isNameStart(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			tokens = append(tokens, queryToken{kind: tokenName, text: src[i:j], offset: i, space: space})
+			i = j
+		case isDigit(c) || (c == '-' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i + 1
+			for j < len(src) && isDigit(src[j]) {
+				j++
+			}
+			tokens = append(tokens, queryToken{kind: tokenNumber, text: src[i:j], offset: i, space: space})
+			i = j
+		case c == '"' || c == '`':
+			j := i + 1
+			for j < len(src) && src[j] != c {
+				if c == '"' && src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) {
+				return nil, &Error{Offset: i, Message: "string is not terminated"}
+			}
+			str, err := strconv.Unquote(src[i : j+1])
+			if err != nil {
+				return nil, &Error{Offset: i, Message: "invalid string"}
+			}
+			tokens = append(tokens, queryToken{kind: tokenString, text: str, offset: i, space: space})
+			i = j + 1
+		default:
+			punct := ""
+			for _, p := range punctuation {
+				if strings.HasPrefix(src[i:], p) {
+					punct = p
+					break
+				}
+			}
+			if punct == "" {
+				return nil, &Error{Offset: i, Message: fmt.Sprintf("unexpected character %q", c)}
+			}
+			tokens = append(tokens, queryToken{kind: tokenPunct, text: punct, offset: i, space: space})
+			i += len(punct)
+		}
+		space = false
+	}
+	return append(tokens, queryToken{kind: tokenEOF, offset: len(src), space: space}), nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type parser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *parser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() queryToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isPunct(text string) bool {
+	t := p.peek()
+	return t.kind == tokenPunct && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if !p.isPunct(text) {
+		return p.errorf("expected %q, found %s", text, describeToken(p.peek()))
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &Error{Offset: p.peek().offset, Message: fmt.Sprintf(format, args...)}
+}
+
+func describeToken(t queryToken) string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// parseQuery parses comma separated selectors
+func parseQuery(src string) ([]*selector, error) {
+	tokens, err := scan(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	selectors := []*selector{}
+	for {
+		s, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+
+		if p.peek().kind == tokenEOF {
+			return selectors, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseSelector parses steps separated by > for children or by whitespace for descendants
+func (p *parser) parseSelector() (*selector, error) {
+	s := &selector{}
+	for {
+		st, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		s.steps = append(s.steps, st)
+
+		t := p.peek()
+		switch {
+		case t.kind == tokenEOF || p.isPunct(","):
+			return s, nil
+		case p.isPunct(">"):
+			p.next()
+			s.combinators = append(s.combinators, combinatorChild)
+		case t.space && (t.kind == tokenName || p.isPunct("*")):
+			s.combinators = append(s.combinators, combinatorDescendant)
+		default:
+			return nil, p.errorf("unexpected %s", describeToken(t))
+		}
+	}
+}
+
+// parseStep parses kind, slot name or * followed by attribute filters
+func (p *parser) parseStep() (*step, error) {
+	t := p.peek()
+	st := &step{kind: syntaxkind.AstNone}
+	switch {
+	case p.isPunct("*"):
+		p.next()
+	case t.kind == tokenName:
+		p.next()
+		if kind, ok := syntaxkind.GetAstKindByName(t.text); ok {
+			st.kind = kind
+		} else if isSlotName(t.text) {
+			st.slot = t.text
+		} else {
+			return nil, &Error{Offset: t.offset, Message: fmt.Sprintf("unknown kind or slot %q", t.text)}
+		}
+	default:
+		return nil, p.errorf("expected kind, slot or *, found %s", describeToken(t))
+	}
+
+	for p.isPunct("[") && !p.peek().space {
+		p.next()
+		a, err := p.parseAttribute()
+		if err != nil {
+			return nil, err
+		}
+		st.attributes = append(st.attributes, a)
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return st, nil
+}
+
+// parseAttribute parses field path optionally followed by the comparison
+func (p *parser) parseAttribute() (*attribute, error) {
+	a := &attribute{}
+	for {
+		t := p.peek()
+		if t.kind != tokenName {
+			return nil, p.errorf("expected field name, found %s", describeToken(t))
+		}
+		if !isFieldName(t.text) && !(t.text == countField && len(a.path) > 0) {
+			return nil, p.errorf("unknown field %q", t.text)
+		}
+		if len(a.path) > 0 && a.path[len(a.path)-1] == countField {
+			return nil, p.errorf("%s must be the last field", countField)
+		}
+		p.next()
+		a.path = append(a.path, t.text)
+
+		if !p.isPunct(".") {
+			break
+		}
+		p.next()
+	}
+
+	t := p.peek()
+	if t.kind != tokenPunct || !isOperator(t.text) {
+		return a, nil
+	}
+	p.next()
+	a.op = t.text
+
+	value := p.peek()
+	switch value.kind {
+	case tokenNumber:
+		n, err := strconv.Atoi(value.text)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", value.text)
+		}
+		a.number = n
+		a.isNumber = true
+	case tokenString, tokenName:
+	default:
+		return nil, p.errorf("expected value, found %s", describeToken(value))
+	}
+	a.value = value.text
+
+	if a.op == "~=" {
+		rx, err := regexp.Compile(a.value)
+		if err != nil {
+			return nil, p.errorf("invalid regular expression: %v", err)
+		}
+		a.rx = rx
+	} else if !a.isNumber && a.op != "=" && a.op != "!=" {
+		return nil, p.errorf("operator %s requires a number", a.op)
+	}
+	p.next()
+	return a, nil
+}
+
+func isOperator(text string) bool {
+	switch text {
+	case "=", "!=", "<", "<=", ">", ">=", "~=":
+		return true
+	}
+	return false
+}
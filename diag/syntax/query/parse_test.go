@@ -0,0 +1,56 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompile(t *testing.T) {
+	valid := []string{
+		"*",
+		"FuncDecl > Body ReturnStmt[Results.count>2]",
+		"FuncDecl>Body>ReturnStmt",
+		"  CallExpr[Fun.Name=println] , CallExpr[Fun.Sel.Name=`Print`]  ",
+		`BasicLit[Value="\"a\\\"b\""]`,
+		"ChanType[Dir=-1]",
+		"Field[Tag][Names.count!=1]",
+		`Ident[Name~="^[a-z]+$"]`,
+	}
+	for _, src := range valid {
+		_, err := query.Compile(src)
+		assert.NoError(t, err, src)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	check := func(src string, expected string) {
+		t.Helper()
+		_, err := query.Compile(src)
+		if assert.Error(t, err, src) {
+			assert.Equal(t, expected, err.Error(), src)
+			_, ok := err.(*query.Error)
+			assert.True(t, ok)
+		}
+	}
+
+	check("", "column 1: expected kind, slot or *, found end of query")
+	check("FuncDecl >", "column 11: expected kind, slot or *, found end of query")
+	check("FuncDecl,", "column 10: expected kind, slot or *, found end of query")
+	check("Func", `column 1: unknown kind or slot "Func"`)
+	check("FuncDecl Bodies", `column 10: unknown kind or slot "Bodies"`)
+	check("FuncDecl [Name=f]", `column 10: unexpected "["`)
+	check("FuncDecl[Name=f", `column 16: expected "]", found end of query`)
+	check("FuncDecl[Nam=f]", `column 10: unknown field "Nam"`)
+	check("FuncDecl[count=1]", `column 10: unknown field "count"`)
+	check("FuncDecl[Body.count.List]", `column 21: count must be the last field`)
+	check("FuncDecl[Body.]", `column 15: expected field name, found "]"`)
+	check("FuncDecl[Name=]", `column 15: expected value, found "]"`)
+	check("FuncDecl[Name>f]", `column 15: operator > requires a number`)
+	check(`Ident[Name~="("]`, "column 13: invalid regular expression: error parsing regexp: missing closing ): `(`")
+	check(`Ident[Name="a]`, "column 12: string is not terminated")
+	check(`Ident[Name="\q"]`, "column 12: invalid string")
+	check("Ident#", `column 6: unexpected character '#'`)
+	check("Ident Ident2", `column 7: unknown kind or slot "Ident2"`)
+}
@@ -0,0 +1,159 @@
+package query
+
+import (
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+)
+
+type combinator int
+
+const (
+	combinatorDescendant combinator = iota
+	combinatorChild
+)
+
+// selector is the sequence of steps, combinators[i] joins steps[i] and steps[i+1]
+type selector struct {
+	steps       []*step
+	combinators []combinator
+}
+
+// step matches node by its kind or its parent slot and attributes, it matches any node if kind is AstNone and slot is empty
+type step struct {
+	kind       syntaxkind.AstKind
+	slot       string
+	attributes []*attribute
+}
+
+// Query is the compiled structural search query.
+//
+// The query consists of comma separated selectors. The selector is the sequence of steps separated
+// by > which matches children or by whitespace which matches descendants, like CSS selectors. The step is
+//
+//	Kind     node of the kind, e.g. CallExpr or FuncDecl
+//	Slot     node in the named field of its parent node, e.g. Body, Results or Fun
+//	*        any node
+//
+// followed by optional attribute filters in brackets. The filter is the field path, e.g. Fun.Name,
+// and the optional comparison with the string, name or number. The path without comparison checks that
+// the field is not empty. The last field of the path may be count which is the number of elements of
+// the list field, or of the statements and fields of blocks and field lists. Identifiers are compared
+// by their names, literals by their values, tokens by their text and other nodes by their source text.
+//
+//	FuncDecl > Body ReturnStmt[Results.count>2]
+//	CallExpr[Fun.Name=println], CallExpr[Fun.Sel.Name~="^Print"]
+//	BinaryExpr[Op="=="][Y=nil]
+type Query struct {
+	src       string
+	selectors []*selector
+}
+
+// Compile compiles the query, the returned error is *Error
+func Compile(src string) (*Query, error) {
+	selectors, err := parseQuery(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{src: src, selectors: selectors}, nil
+}
+
+// MustCompile compiles the query and panics if the query is invalid
+func MustCompile(src string) *Query {
+	q, err := Compile(src)
+	if err != nil {
+		panic("invalid query " + src + ": " + err.Error())
+	}
+	return q
+}
+
+// String returns source of the query
+func (q *Query) String() string {
+	return q.src
+}
+
+// GetKinds returns kinds of nodes which can match the query, e.g. to register rule node actions.
+// Nil is returned if nodes of any kind can match.
+func (q *Query) GetKinds() []syntaxkind.AstKind {
+	kinds := []syntaxkind.AstKind{}
+	seen := map[syntaxkind.AstKind]bool{}
+	for _, s := range q.selectors {
+		kind := s.steps[len(s.steps)-1].kind
+		if kind == syntaxkind.AstNone {
+			return nil
+		}
+		if !seen[kind] {
+			seen[kind] = true
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
+}
+
+// Match checks if the node of the document matches the query
+func (q *Query) Match(doc *syntax.Document, node syntax.Node) bool {
+	for _, s := range q.selectors {
+		if s.match(doc, node, len(s.steps)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns nodes of the document which match the query in the document order
+func (q *Query) Find(doc *syntax.Document) []syntax.Node {
+	nodes := []syntax.Node{}
+	if doc.Root != nil {
+		q.find(doc, doc.Root, &nodes)
+	}
+	return nodes
+}
+
+func (q *Query) find(doc *syntax.Document, node syntax.Node, nodes *[]syntax.Node) {
+	if q.Match(doc, node) {
+		*nodes = append(*nodes, node)
+	}
+	for _, elmt := range node.GetElements() {
+		if child, ok := elmt.(syntax.Node); ok {
+			q.find(doc, child, nodes)
+		}
+	}
+}
+
+// match checks if the node matches step i and its ancestors match previous steps
+func (s *selector) match(doc *syntax.Document, node syntax.Node, i int) bool {
+	if !s.steps[i].match(doc, node) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	parent := node.GetParent()
+	if s.combinators[i-1] == combinatorChild {
+		return parent != nil && s.match(doc, parent, i-1)
+	}
+	for ; parent != nil; parent = parent.GetParent() {
+		if s.match(doc, parent, i-1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *step) match(doc *syntax.Document, node syntax.Node) bool {
+	if st.kind != syntaxkind.AstNone && syntaxkind.GetAstKind(node.GetAstNode()) != st.kind {
+		return false
+	}
+	if st.slot != "" {
+		parent := node.GetParent()
+		if parent == nil || getSlot(parent, node) != st.slot {
+			return false
+		}
+	}
+	for _, a := range st.attributes {
+		if !a.match(doc, node) {
+			return false
+		}
+	}
+	return true
+}
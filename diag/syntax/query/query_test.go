@@ -0,0 +1,113 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/query"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+	"github.com/stretchr/testify/assert"
+)
+
+const querySrc = `package a
+
+import "fmt"
+
+// f returns values
+func f(a, b int) (int, int, error) {
+	if a == b {
+		return a, b, nil
+	}
+	go func() {
+		return
+	}()
+	fmt.Println(a + b)
+	println("a")
+	return 1, 2, fmt.Errorf("failed")
+}
+
+func g() int {
+	var x = func() (int, int, int) { return 1, 2, 3 }
+	_ = x
+	return 0
+}
+`
+
+// find returns source texts of the nodes matching the query
+func find(t *testing.T, src string) []string {
+	t.Helper()
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", querySrc)
+	assert.NoError(t, err)
+
+	q, err := query.Compile(src)
+	if !assert.NoError(t, err) {
+		return nil
+	}
+
+	texts := []string{}
+	for _, node := range q.Find(doc) {
+		texts = append(texts, doc.Text.GetSubText(doc.GetSpan(node)))
+	}
+	return texts
+}
+
+func TestFind(t *testing.T) {
+	assert.Equal(t, []string{"return a, b, nil", `return 1, 2, fmt.Errorf("failed")`, "return 1, 2, 3"},
+		find(t, "ReturnStmt[Results.count>2]"))
+	assert.Equal(t, []string{"return a, b, nil", `return 1, 2, fmt.Errorf("failed")`, "return 1, 2, 3"},
+		find(t, "FuncDecl > Body ReturnStmt[Results.count>2]"))
+	assert.Equal(t, []string{"return a, b, nil", `return 1, 2, fmt.Errorf("failed")`},
+		find(t, "FuncDecl[Name=f] > Body ReturnStmt[Results.count>2]"))
+	assert.Equal(t, []string{`return 1, 2, fmt.Errorf("failed")`, "return 0"},
+		find(t, "FuncDecl > Body > ReturnStmt"))
+	assert.Equal(t, []string{"return"}, find(t, "ReturnStmt[Results.count=0]"))
+	assert.Equal(t, []string{"return"}, find(t, "GoStmt ReturnStmt"))
+	assert.Equal(t, []string{"f", "g"}, find(t, "FuncDecl > Name"))
+	assert.Equal(t, []string{"a", "b"}, find(t, "FuncDecl[Name=f] Params Names"))
+	assert.Equal(t, []string{"int", "int", "error", "int"}, find(t, "FuncDecl > Type > Results Type"))
+	assert.Empty(t, find(t, "Body > Type"))
+}
+
+func TestFind_Attributes(t *testing.T) {
+	assert.Equal(t, []string{`println("a")`}, find(t, "CallExpr[Fun.Name=println]"))
+	assert.Equal(t, []string{`println("a")`}, find(t, `CallExpr[Fun="println"]`))
+	assert.Equal(t, []string{"fmt.Println(a + b)", `fmt.Errorf("failed")`}, find(t, "CallExpr[Fun.X=fmt]"))
+	assert.Equal(t, []string{`fmt.Errorf("failed")`}, find(t, `CallExpr[Fun.Sel.Name~="^Err"][Args.count=1]`))
+	assert.Equal(t, []string{`"fmt"`, `"a"`, `"failed"`}, find(t, "BasicLit[Kind=STRING]"))
+	assert.Equal(t, []string{`"a"`}, find(t, `BasicLit[Value="\"a\""]`))
+	assert.Equal(t, []string{"a == b"}, find(t, `BinaryExpr[Op="=="]`))
+	assert.Equal(t, []string{"a + b"}, find(t, `BinaryExpr[Op!="=="][X=a]`))
+	assert.Equal(t, []string{"fmt.Println(a + b)"}, find(t, `ExprStmt[X="fmt.Println(a + b)"] > *`))
+	assert.Equal(t, []string{"f"}, find(t, `FuncDecl[Doc][Body.count>=5] > Name`))
+	assert.Equal(t, []string{"g"}, find(t, `FuncDecl[Body.count<4] > Name`))
+	assert.Equal(t, []string{"var x = func() (int, int, int) { return 1, 2, 3 }"}, find(t, `GenDecl[Tok=var]`))
+	assert.Empty(t, find(t, `FuncDecl[Recv]`))
+	assert.Empty(t, find(t, `ReturnStmt[Results.count>3]`))
+	assert.Empty(t, find(t, `CallExpr[Fun.Sel.Name=nothing]`))
+}
+
+func TestFind_Selectors(t *testing.T) {
+	assert.Equal(t, []string{"f", "println", "g"}, find(t, "FuncDecl > Name, CallExpr > Fun[Name=println]"))
+	assert.Equal(t, 2, len(find(t, "FuncDecl, FuncDecl[Name=g]")))
+	assert.Equal(t, 11, len(find(t, "Body > *")))
+	assert.Equal(t, 5, len(find(t, "FuncDecl[Name=f] > Body > *")))
+	assert.Equal(t, []string{"return", "return 1, 2, 3"}, find(t, "FuncLit * ReturnStmt"))
+}
+
+func TestMatch(t *testing.T) {
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", querySrc)
+	assert.NoError(t, err)
+
+	q := query.MustCompile("File > FuncDecl")
+	assert.True(t, q.Match(doc, doc.Root.GetElements()[3].(syntax.Node)))
+	assert.False(t, q.Match(doc, doc.Root))
+	assert.Equal(t, "File > FuncDecl", q.String())
+	assert.Panics(t, func() { query.MustCompile("File >") })
+}
+
+func TestGetKinds(t *testing.T) {
+	kinds := query.MustCompile("FuncDecl ReturnStmt, CallExpr[Fun.Name=f], Body > ReturnStmt").GetKinds()
+	assert.Equal(t, []syntaxkind.AstKind{syntaxkind.AstReturnStmt, syntaxkind.AstCallExpr}, kinds)
+	assert.Nil(t, query.MustCompile("CallExpr, FuncDecl > Body").GetKinds())
+	assert.Nil(t, query.MustCompile("FuncDecl *").GetKinds())
+}
@@ -0,0 +1,259 @@
+package query
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+)
+
+// countField is the last field of the attribute path which returns number of elements of the field
+const countField = "count"
+
+// nodeTypes are types of ast nodes whose fields can be used in queries
+var nodeTypes = []ast.Node{
+	&ast.Comment{}, &ast.CommentGroup{}, &ast.Field{}, &ast.FieldList{},
+	&ast.BadExpr{}, &ast.Ident{}, &ast.Ellipsis{}, &ast.BasicLit{}, &ast.FuncLit{}, &ast.CompositeLit{},
+	&ast.ParenExpr{}, &ast.SelectorExpr{}, &ast.IndexExpr{}, &ast.SliceExpr{}, &ast.TypeAssertExpr{},
+	&ast.CallExpr{}, &ast.StarExpr{}, &ast.UnaryExpr{}, &ast.BinaryExpr{}, &ast.KeyValueExpr{},
+	&ast.ArrayType{}, &ast.StructType{}, &ast.FuncType{}, &ast.InterfaceType{}, &ast.MapType{}, &ast.ChanType{},
+	&ast.BadStmt{}, &ast.DeclStmt{}, &ast.EmptyStmt{}, &ast.LabeledStmt{}, &ast.ExprStmt{}, &ast.SendStmt{},
+	&ast.IncDecStmt{}, &ast.AssignStmt{}, &ast.GoStmt{}, &ast.DeferStmt{}, &ast.ReturnStmt{}, &ast.BranchStmt{},
+	&ast.BlockStmt{}, &ast.IfStmt{}, &ast.CaseClause{}, &ast.SwitchStmt{}, &ast.TypeSwitchStmt{},
+	&ast.CommClause{}, &ast.SelectStmt{}, &ast.ForStmt{}, &ast.RangeStmt{},
+	&ast.ImportSpec{}, &ast.ValueSpec{}, &ast.TypeSpec{},
+	&ast.BadDecl{}, &ast.GenDecl{}, &ast.FuncDecl{}, &ast.File{},
+}
+
+var (
+	nodeType  = reflect.TypeOf((*ast.Node)(nil)).Elem()
+	posType   = reflect.TypeOf(token.NoPos)
+	tokenType = reflect.TypeOf(token.ILLEGAL)
+)
+
+// slotNames are names of the fields which contain nodes, fieldNames are names of all fields except positions
+var slotNames, fieldNames = getFieldNames()
+
+func getFieldNames() (map[string]bool, map[string]bool) {
+	slots := map[string]bool{}
+	fields := map[string]bool{}
+	for _, node := range nodeTypes {
+		t := reflect.TypeOf(node).Elem()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Type == posType {
+				continue
+			}
+			fields[f.Name] = true
+			if isNodeType(f.Type) || (f.Type.Kind() == reflect.Slice && isNodeType(f.Type.Elem())) {
+				slots[f.Name] = true
+			}
+		}
+	}
+	return slots, fields
+}
+
+func isNodeType(t reflect.Type) bool {
+	return t.Implements(nodeType)
+}
+
+func isSlotName(name string) bool {
+	return slotNames[name]
+}
+
+func isFieldName(name string) bool {
+	return fieldNames[name]
+}
+
+// getSlot returns name of the parent field which contains the node
+func getSlot(parent syntax.Node, node syntax.Node) string {
+	v := reflect.ValueOf(parent.GetAstNode())
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ""
+	}
+
+	child := node.GetAstNode()
+	s := v.Elem()
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		switch {
+		case f.Kind() == reflect.Slice:
+			for j := 0; j < f.Len(); j++ {
+				if isSameNode(f.Index(j), child) {
+					return s.Type().Field(i).Name
+				}
+			}
+		case isSameNode(f, child):
+			return s.Type().Field(i).Name
+		}
+	}
+	return ""
+}
+
+func isSameNode(v reflect.Value, node ast.Node) bool {
+	if (v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface) || v.IsNil() || !v.CanInterface() {
+		return false
+	}
+	n, ok := v.Interface().(ast.Node)
+	return ok && n == node
+}
+
+// value is the value of the attribute path
+type value struct {
+	str      string
+	number   int
+	isNumber bool
+	present  bool
+}
+
+// getValue returns value of the field path of the node. Identifiers are converted to their names,
+// literals to their values, tokens to their text and other nodes to their source text.
+func getValue(doc *syntax.Document, node ast.Node, path []string) value {
+	v := reflect.ValueOf(node)
+	for _, name := range path {
+		if name == countField {
+			return getCount(v)
+		}
+
+		v = indirect(v)
+		if !v.IsValid() || v.Kind() != reflect.Struct {
+			return value{}
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return value{}
+		}
+	}
+	return convertValue(doc, v)
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func getCount(v reflect.Value) value {
+	count := 0
+	if v.Kind() == reflect.Slice {
+		count = v.Len()
+	} else if s := indirect(v); s.IsValid() {
+		count = 1
+		if s.Kind() == reflect.Struct {
+			// blocks and field lists are counted by their elements
+			if list := s.FieldByName("List"); list.Kind() == reflect.Slice {
+				count = list.Len()
+			}
+		}
+	}
+	return value{str: strconv.Itoa(count), number: count, isNumber: true, present: true}
+}
+
+func convertValue(doc *syntax.Document, v reflect.Value) value {
+	if v.Type() == tokenType {
+		tok := token.Token(v.Int())
+		return value{str: tok.String(), present: tok != token.ILLEGAL}
+	}
+
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return value{}
+		}
+		switch n := v.Interface().(type) {
+		case *ast.Ident:
+			return value{str: n.Name, present: true}
+		case *ast.BasicLit:
+			return value{str: n.Value, present: true}
+		case ast.Node:
+			return value{str: getSourceText(doc, n), present: true}
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return value{str: v.String(), present: v.Len() > 0}
+	case reflect.Bool:
+		return value{str: strconv.FormatBool(v.Bool()), present: v.Bool()}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := int(v.Int())
+		return value{str: strconv.Itoa(n), number: n, isNumber: true, present: n != 0}
+	case reflect.Slice:
+		return value{present: v.Len() > 0}
+	}
+	return value{}
+}
+
+func getSourceText(doc *syntax.Document, node ast.Node) string {
+	if doc == nil || !doc.Contains(node.Pos()) {
+		return ""
+	}
+	start := doc.GetOffset(node.Pos())
+	end := doc.GetOffset(node.End())
+	if end < start {
+		return ""
+	}
+	return doc.Text.String()[start:end]
+}
+
+// attribute filters nodes by the value of the field path, op is empty if the field must not be empty
+type attribute struct {
+	path     []string
+	op       string
+	value    string
+	number   int
+	isNumber bool
+	rx       *regexp.Regexp
+}
+
+func (a *attribute) match(doc *syntax.Document, node syntax.Node) bool {
+	v := getValue(doc, node.GetAstNode(), a.path)
+	if a.op == "" {
+		return v.present
+	}
+	if !v.present && v.str == "" {
+		// missing fields do not match comparisons
+		return false
+	}
+
+	if a.op == "~=" {
+		return a.rx.MatchString(v.str)
+	}
+
+	if a.isNumber && v.isNumber {
+		return compare(v.number-a.number, a.op)
+	}
+
+	switch a.op {
+	case "=":
+		return v.str == a.value
+	case "!=":
+		return v.str != a.value
+	}
+	return false
+}
+
+func compare(diff int, op string) bool {
+	switch op {
+	case "=":
+		return diff == 0
+	case "!=":
+		return diff != 0
+	case "<":
+		return diff < 0
+	case "<=":
+		return diff <= 0
+	case ">":
+		return diff > 0
+	case ">=":
+		return diff >= 0
+	}
+	return false
+}
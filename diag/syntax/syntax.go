@@ -19,6 +19,8 @@ const (
 type Element interface {
 	GetParent() Node
 	GetElementType() ElementType
+	GetPos() token.Pos
+	GetEnd() token.Pos
 }
 
 // Node represents syntax node
@@ -63,6 +65,12 @@ func NewElementFromAst(node ast.Node) Node {
 	return newElementFromAstAndParent(nil, node)
 }
 
+// NewElementFromAstAndParent creates new syntax node element with the given parent,
+// e.g. typed node for a node of the document tree
+func NewElementFromAstAndParent(parent Node, node ast.Node) Node {
+	return newElementFromAstAndParent(parent, node)
+}
+
 // IsNode returns true if element is syntax node
 func IsNode(elmt Element) bool {
 	if elmt == nil {
@@ -114,6 +122,26 @@ func (n *nodeImpl) GetElements() []Element {
 	return n.Elements
 }
 
+// GetPos returns position of the first element of the node, or ast node position if node has no elements
+func (n *nodeImpl) GetPos() token.Pos {
+	for _, elmt := range n.Elements {
+		if pos := elmt.GetPos(); pos.IsValid() {
+			return pos
+		}
+	}
+	return n.AstNode.Pos()
+}
+
+// GetEnd returns end position of the last element of the node, or ast node end if node has no elements
+func (n *nodeImpl) GetEnd() token.Pos {
+	for i := len(n.Elements) - 1; i >= 0; i-- {
+		if end := n.Elements[i].GetEnd(); end.IsValid() {
+			return end
+		}
+	}
+	return n.AstNode.End()
+}
+
 type tokenImpl struct {
 	Parent Node
 	Pos    token.Pos
@@ -137,6 +165,17 @@ func (t *tokenImpl) GetKind() token.Token {
 	return t.Kind
 }
 
+func (t *tokenImpl) GetPos() token.Pos {
+	return t.Pos
+}
+
+func (t *tokenImpl) GetEnd() token.Pos {
+	if !t.Pos.IsValid() {
+		return token.NoPos
+	}
+	return t.Pos + token.Pos(len(t.Text))
+}
+
 func getNodeImpl(parent Node, node ast.Node) *nodeImpl {
 	if node == nil {
 		return nil
@@ -174,6 +213,13 @@ func newToken(parent Node, pos token.Pos, text string, kind token.Token) Token {
 	return r
 }
 
+func newOptionalTokenByKind(parent Node, pos token.Pos, kind token.Token) Token {
+	if !pos.IsValid() {
+		return nil
+	}
+	return newTokenByKind(parent, pos, kind)
+}
+
 func tokenNode(parent Node, pos token.Pos, text string, kind token.Token) Token {
 	t := &tokenImpl{
 		Parent: parent,
@@ -201,6 +247,15 @@ func appendToken(elmts []Element, parent Node, pos token.Pos, text string, kind
 	return append(elmts, elmt)
 }
 
+// appendOptionalToken appends token only if its position is recorded in the ast,
+// e.g. parens of a result list or ellipsis of a call are optional in the source
+func appendOptionalToken(elmts []Element, parent Node, pos token.Pos, text string, kind token.Token) []Element {
+	if !pos.IsValid() {
+		return elmts
+	}
+	return appendToken(elmts, parent, pos, text, kind)
+}
+
 func appendToken2(elmts []Element, token Token) []Element {
 	if isNilToken(token) {
 		return elmts
@@ -267,6 +322,26 @@ func appendIdents2(elmts []Element, idents []*Ident) []Element {
 	return elmts
 }
 
+func appendSpecs(elmts []Element, parent Node, specs []ast.Spec) []Element {
+	if specs == nil {
+		return elmts
+	}
+	for _, s := range specs {
+		elmts = appendElement(elmts, parent, s)
+	}
+	return elmts
+}
+
+func appendDecls(elmts []Element, parent Node, decls []ast.Decl) []Element {
+	if decls == nil {
+		return elmts
+	}
+	for _, d := range decls {
+		elmts = appendElement(elmts, parent, d)
+	}
+	return elmts
+}
+
 func appendComments(elmts []Element, parent Node, comments []*ast.Comment) []Element {
 	if comments == nil {
 		return elmts
@@ -490,9 +565,10 @@ func loadElements(parent Node, node ast.Node) []Element {
 		return elmts
 
 	case *ast.FieldList:
-		elmts = appendLParenToken(elmts, parent, n.Opening)
+		opening, closing := getFieldListBrackets(parent)
+		elmts = appendOptionalToken(elmts, parent, n.Opening, opening.String(), opening)
 		elmts = appendFields(elmts, parent, n.List)
-		elmts = appendRParenToken(elmts, parent, n.Closing)
+		elmts = appendOptionalToken(elmts, parent, n.Closing, closing.String(), closing)
 		return elmts
 
 	case *ast.BadExpr:
@@ -561,7 +637,7 @@ func loadElements(parent Node, node ast.Node) []Element {
 		elmts = appendElement(elmts, parent, n.Fun)
 		elmts = appendLParenToken(elmts, parent, n.Lparen)
 		elmts = appendExprs(elmts, parent, n.Args)
-		elmts = appendToken(elmts, parent, n.Ellipsis, "...", token.ELLIPSIS)
+		elmts = appendOptionalToken(elmts, parent, n.Ellipsis, "...", token.ELLIPSIS)
 		elmts = appendRParenToken(elmts, parent, n.Rparen)
 		return elmts
 
@@ -599,7 +675,10 @@ func loadElements(parent Node, node ast.Node) []Element {
 		return elmts
 
 	case *ast.FuncType:
-		elmts = appendToken(elmts, parent, n.Func, "func", token.FUNC)
+		// func keyword of a function declaration belongs to the declaration
+		if !isFuncDeclType(parent) {
+			elmts = appendOptionalToken(elmts, parent, n.Func, "func", token.FUNC)
+		}
 		elmts = appendElement(elmts, parent, n.Params)
 		elmts = appendElement(elmts, parent, n.Results)
 		return elmts
@@ -618,7 +697,7 @@ func loadElements(parent Node, node ast.Node) []Element {
 	case *ast.ChanType:
 		if n.Begin != n.Arrow {
 			elmts = appendToken(elmts, parent, n.Begin, "chan", token.CHAN)
-			elmts = appendToken(elmts, parent, n.Arrow, "<-", token.ARROW)
+			elmts = appendOptionalToken(elmts, parent, n.Arrow, "<-", token.ARROW)
 		} else {
 			elmts = appendToken(elmts, parent, n.Arrow, "<-", token.ARROW)
 		}
@@ -701,7 +780,7 @@ func loadElements(parent Node, node ast.Node) []Element {
 		return elmts
 
 	case *ast.CaseClause:
-		elmts = appendToken(elmts, parent, n.Case, token.CASE.String(), token.CASE)
+		elmts = appendCaseToken(elmts, parent, n.Case, n.List == nil)
 		elmts = appendExprs(elmts, parent, n.List)
 		elmts = appendToken(elmts, parent, n.Colon, token.COLON.String(), token.COLON)
 		elmts = appendStmts(elmts, parent, n.Body)
@@ -722,7 +801,7 @@ func loadElements(parent Node, node ast.Node) []Element {
 		return elmts
 
 	case *ast.CommClause:
-		elmts = appendToken(elmts, parent, n.Case, token.CASE.String(), token.CASE)
+		elmts = appendCaseToken(elmts, parent, n.Case, n.Comm == nil)
 		elmts = appendElement(elmts, parent, n.Comm)
 		elmts = appendToken(elmts, parent, n.Colon, token.COLON.String(), token.COLON)
 		elmts = appendStmts(elmts, parent, n.Body)
@@ -745,7 +824,7 @@ func loadElements(parent Node, node ast.Node) []Element {
 		elmts = appendToken(elmts, parent, n.For, token.FOR.String(), token.FOR)
 		elmts = appendElement(elmts, parent, n.Key)
 		elmts = appendElement(elmts, parent, n.Value)
-		elmts = appendToken(elmts, parent, n.TokPos, n.Tok.String(), n.Tok)
+		elmts = appendOptionalToken(elmts, parent, n.TokPos, n.Tok.String(), n.Tok)
 		elmts = appendElement(elmts, parent, n.X)
 		elmts = appendElement(elmts, parent, n.Body)
 		return elmts
@@ -756,6 +835,85 @@ func loadElements(parent Node, node ast.Node) []Element {
 		elmts = appendElement(elmts, parent, n.Path)
 		elmts = appendElement(elmts, parent, n.Comment)
 		return elmts
+
+	case *ast.ValueSpec:
+		elmts = appendElement(elmts, parent, n.Doc)
+		elmts = appendIdents(elmts, parent, n.Names)
+		elmts = appendElement(elmts, parent, n.Type)
+		elmts = appendExprs(elmts, parent, n.Values)
+		elmts = appendElement(elmts, parent, n.Comment)
+		return elmts
+
+	case *ast.TypeSpec:
+		elmts = appendElement(elmts, parent, n.Doc)
+		elmts = appendElement(elmts, parent, n.Name)
+		if n.Assign.IsValid() {
+			elmts = appendToken(elmts, parent, n.Assign, token.ASSIGN.String(), token.ASSIGN)
+		}
+		elmts = appendElement(elmts, parent, n.Type)
+		elmts = appendElement(elmts, parent, n.Comment)
+		return elmts
+
+	case *ast.BadDecl:
+		return nil
+
+	case *ast.GenDecl:
+		elmts = appendElement(elmts, parent, n.Doc)
+		elmts = appendToken(elmts, parent, n.TokPos, n.Tok.String(), n.Tok)
+		if n.Lparen.IsValid() {
+			elmts = appendLParenToken(elmts, parent, n.Lparen)
+		}
+		elmts = appendSpecs(elmts, parent, n.Specs)
+		if n.Rparen.IsValid() {
+			elmts = appendRParenToken(elmts, parent, n.Rparen)
+		}
+		return elmts
+
+	case *ast.FuncDecl:
+		elmts = appendElement(elmts, parent, n.Doc)
+		if n.Type != nil {
+			elmts = appendToken(elmts, parent, n.Type.Func, "func", token.FUNC)
+		}
+		elmts = appendElement(elmts, parent, n.Recv)
+		elmts = appendElement(elmts, parent, n.Name)
+		elmts = appendElement(elmts, parent, n.Type)
+		elmts = appendElement(elmts, parent, n.Body)
+		return elmts
+
+	case *ast.File:
+		elmts = appendElement(elmts, parent, n.Doc)
+		elmts = appendToken(elmts, parent, n.Package, token.PACKAGE.String(), token.PACKAGE)
+		elmts = appendElement(elmts, parent, n.Name)
+		elmts = appendDecls(elmts, parent, n.Decls)
+		return elmts
 	}
 	return nil
 }
+
+func appendCaseToken(elmts []Element, parent Node, pos token.Pos, isDefault bool) []Element {
+	if isDefault {
+		return appendToken(elmts, parent, pos, token.DEFAULT.String(), token.DEFAULT)
+	}
+	return appendToken(elmts, parent, pos, token.CASE.String(), token.CASE)
+}
+
+// getFieldListBrackets returns brackets of the field list, fields of struct and methods of interface are
+// enclosed in braces, parameters and results in parens
+func getFieldListBrackets(node Node) (token.Token, token.Token) {
+	if parent := node.GetParent(); parent != nil {
+		switch parent.GetAstNode().(type) {
+		case *ast.StructType, *ast.InterfaceType:
+			return token.LBRACE, token.RBRACE
+		}
+	}
+	return token.LPAREN, token.RPAREN
+}
+
+func isFuncDeclType(node Node) bool {
+	parent := node.GetParent()
+	if parent == nil {
+		return false
+	}
+	_, ok := parent.GetAstNode().(*ast.FuncDecl)
+	return ok
+}
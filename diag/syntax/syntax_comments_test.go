@@ -3,8 +3,8 @@ package syntax_test
 import (
 	"testing"
 
+	"github.com/a6cexz/goanalyzer/diag/syntax"
 	"github.com/stretchr/testify/assert"
-	"maxmapps.com/goanalyzer/diag/syntax"
 )
 
 func TestNewCommentGroupNode(t *testing.T) {
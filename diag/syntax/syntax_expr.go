@@ -142,7 +142,7 @@ func newFuncType(parent Node, node *ast.FuncType) *FuncType {
 	}
 	r := &FuncType{}
 	r.nodeImpl = getNodeImpl(parent, node)
-	r.FuncToken = newTokenByKind(r, node.Func, token.FUNC)
+	r.FuncToken = newOptionalTokenByKind(r, node.Func, token.FUNC)
 	r.Params = newFieldList(r, node.Params)
 	r.Results = newFieldList(r, node.Results)
 	r.Elements = getElements(r)
@@ -2,7 +2,6 @@ package syntax
 
 import (
 	"go/ast"
-	"go/token"
 )
 
 // Field node
@@ -56,9 +55,10 @@ func newFieldList(parent Node, node *ast.FieldList) *FieldList {
 	}
 	r := &FieldList{}
 	r.nodeImpl = getNodeImpl(parent, node)
-	r.Opening = newTokenByKind(r, node.Opening, token.LPAREN)
+	opening, closing := getFieldListBrackets(r)
+	r.Opening = newOptionalTokenByKind(r, node.Opening, opening)
 	r.List = newFields(r, node.List)
-	r.Closing = newTokenByKind(r, node.Closing, token.RPAREN)
+	r.Closing = newOptionalTokenByKind(r, node.Closing, closing)
 	r.Elements = getElements(r)
 	return r
 }
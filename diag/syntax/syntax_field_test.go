@@ -4,6 +4,9 @@ import (
 	"go/ast"
 	"go/token"
 	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestFieldNode(t *testing.T) {
@@ -100,3 +103,26 @@ elmnts: [
 	}
 	checkSyntaxTree2(t, e, fieldList)
 }
+
+func TestFieldListBrackets(t *testing.T) {
+	src := "package a\n\ntype S struct{ a int }\n\ntype I interface{ M(a int) (n int) }\n"
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", src)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"{", "}", "{", "(", ")", "(", ")", "}"}, getFieldListBrackets(doc.Root))
+}
+
+// getFieldListBrackets returns texts of the opening and closing tokens of field lists in document order
+func getFieldListBrackets(node syntax.Node) []string {
+	brackets := []string{}
+	for _, elmt := range node.GetElements() {
+		if tok, ok := elmt.(syntax.Token); ok {
+			if _, ok := node.GetAstNode().(*ast.FieldList); ok {
+				brackets = append(brackets, tok.GetText())
+			}
+			continue
+		}
+		brackets = append(brackets, getFieldListBrackets(elmt.(syntax.Node))...)
+	}
+	return brackets
+}
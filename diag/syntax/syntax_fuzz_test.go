@@ -0,0 +1,34 @@
+//go:build go1.18
+// +build go1.18
+
+package syntax_test
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+)
+
+func FuzzGeneratedProgram(f *testing.F) {
+	for seed := int64(0); seed < 10; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		checkSyntaxInvariants(t, asttest.GenerateProgram(seed))
+	})
+}
+
+func FuzzSource(f *testing.F) {
+	for seed := int64(0); seed < 10; seed++ {
+		f.Add(asttest.GenerateProgram(seed))
+	}
+	f.Add("package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n")
+	f.Fuzz(func(t *testing.T, src string) {
+		if _, err := parser.ParseFile(token.NewFileSet(), "source.go", src, parser.ParseComments); err != nil {
+			t.Skip("invariants are checked only for valid sources")
+		}
+		checkSyntaxInvariants(t, src)
+	})
+}
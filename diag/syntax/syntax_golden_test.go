@@ -0,0 +1,11 @@
+package syntax_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+)
+
+func TestGolden(t *testing.T) {
+	asttest.RunGolden(t, "testdata/golden")
+}
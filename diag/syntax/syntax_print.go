@@ -4,8 +4,31 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// PrintFormat is the format of printed syntax trees
+type PrintFormat int
+
+// Print formats
+const (
+	// PrintFormatTree prints nodes with their parents and elements, it is the format of PrintTo
+	PrintFormatTree PrintFormat = iota
+	// PrintFormatCompact prints one line per element with its kind and position
+	PrintFormatCompact
 )
 
+// PrintOptions controls printing of syntax trees
+type PrintOptions struct {
+	Format PrintFormat
+	// MaxDepth limits depth of printed nodes, elements of deeper nodes are elided. Zero means no limit.
+	MaxDepth int
+	// Document is used to print line positions in compact format, token positions are printed if it is nil
+	Document *Document
+}
+
 // Print prints elmt to std output
 func Print(elmt Element) {
 	var buffer bytes.Buffer
@@ -16,12 +39,75 @@ func Print(elmt Element) {
 
 // PrintTo prints elmt to the given writer
 func PrintTo(w io.Writer, elmt Element) {
-	printSyntaxRec(w, elmt, "")
+	printSyntaxRec(w, elmt, "", 0, PrintOptions{})
+}
+
+// PrintWithOptions prints elmt to the given writer using the options
+func PrintWithOptions(w io.Writer, elmt Element, options PrintOptions) {
+	printSyntaxRec(w, elmt, "", 0, options)
+}
+
+// PrintPathTo prints ancestors of elmt from the root followed by elmt, all printed in the format of the options.
+// Ancestors are printed without their other elements and elements of elmt are printed only if MaxDepth is set.
+func PrintPathTo(w io.Writer, elmt Element, options PrintOptions) {
+	ancestors := []Node{}
+	for parent := elmt.GetParent(); parent != nil; parent = parent.GetParent() {
+		ancestors = append([]Node{parent}, ancestors...)
+	}
+
+	unit := getIndentUnit(options.Format)
+	indent := ""
+	for _, node := range ancestors {
+		printPathNode(w, node, indent, options)
+		indent += unit
+	}
+
+	depth := 0
+	if options.MaxDepth == 0 {
+		// elements of elmt are elided
+		options.MaxDepth, depth = 1, 1
+	}
+	printSyntaxRec(w, elmt, indent, depth, options)
+
+	if options.Format != PrintFormatCompact {
+		for range ancestors {
+			indent = strings.TrimSuffix(indent, unit)
+			fmt.Fprintf(w, "%v]\n", indent)
+		}
+	}
+}
+
+// printPathNode prints the ancestor node without its elements, elements of tree format are closed by PrintPathTo
+func printPathNode(w io.Writer, node Node, indent string, options PrintOptions) {
+	if options.Format == PrintFormatCompact {
+		fmt.Fprintf(w, "%v%v\n", indent, formatCompactNode(node, options.Document))
+		return
+	}
+
+	fmt.Fprintf(w, "%vnode %T\n", indent, node.GetAstNode())
+	if parent := node.GetParent(); parent != nil {
+		fmt.Fprintf(w, "%vparent %T\n", indent, parent.GetAstNode())
+	} else {
+		fmt.Fprintf(w, "%vparent <nil>\n", indent)
+	}
+	fmt.Fprintf(w, "%velmnts: [\n", indent)
 }
 
-func printSyntaxRec(w io.Writer, elmt Element, indent string) {
+func getIndentUnit(format PrintFormat) string {
+	if format == PrintFormatCompact {
+		return "  "
+	}
+	return "\t"
+}
+
+func printSyntaxRec(w io.Writer, elmt Element, indent string, depth int, options PrintOptions) {
+	if options.Format == PrintFormatCompact {
+		printCompactRec(w, elmt, indent, depth, options)
+		return
+	}
+
 	if IsNode(elmt) {
-		printNodeRec(w, elmt.(Node), indent)
+		printNodeRec(w, elmt.(Node), indent, depth, options)
 		return
 	}
 
@@ -31,7 +117,7 @@ func printSyntaxRec(w io.Writer, elmt Element, indent string) {
 	}
 }
 
-func printNodeRec(w io.Writer, node Node, indent string) {
+func printNodeRec(w io.Writer, node Node, indent string, depth int, options PrintOptions) {
 	fmt.Fprintf(w, "%vnode %T\n", indent, node.GetAstNode())
 
 	parent := node.GetParent()
@@ -43,10 +129,12 @@ func printNodeRec(w io.Writer, node Node, indent string) {
 
 	elmnts := node.GetElements()
 	count := len(elmnts)
-	if count > 0 {
+	if count > 0 && isMaxDepth(depth, options) {
+		fmt.Fprintf(w, "%velmnts: [...]\n", indent)
+	} else if count > 0 {
 		fmt.Fprintf(w, "%velmnts: [\n", indent)
 		for i, elmnt := range elmnts {
-			printSyntaxRec(w, elmnt, "\t"+indent)
+			printSyntaxRec(w, elmnt, "\t"+indent, depth+1, options)
 			if i < count-1 {
 				fmt.Fprintf(w, "%v\n", indent)
 			}
@@ -61,3 +149,47 @@ func printTokenRec(w io.Writer, token Token, indent string) {
 	fmt.Fprint(w, indent)
 	fmt.Fprintf(w, "token %v %v\n", token.GetText(), token.GetKind())
 }
+
+func printCompactRec(w io.Writer, elmt Element, indent string, depth int, options PrintOptions) {
+	if IsToken(elmt) {
+		token := elmt.(Token)
+		fmt.Fprintf(w, "%vtoken %v %q %v\n", indent, token.GetKind(), token.GetText(), formatPosition(elmt, options.Document))
+		return
+	}
+
+	if !IsNode(elmt) || isNilNode2(elmt.(Node)) {
+		return
+	}
+
+	node := elmt.(Node)
+	elmnts := node.GetElements()
+	if len(elmnts) > 0 && isMaxDepth(depth, options) {
+		fmt.Fprintf(w, "%v%v ...\n", indent, formatCompactNode(node, options.Document))
+		return
+	}
+
+	fmt.Fprintf(w, "%v%v\n", indent, formatCompactNode(node, options.Document))
+	for _, elmnt := range elmnts {
+		printCompactRec(w, elmnt, indent+"  ", depth+1, options)
+	}
+}
+
+func isMaxDepth(depth int, options PrintOptions) bool {
+	return options.MaxDepth > 0 && depth >= options.MaxDepth
+}
+
+// formatCompactNode returns node type name without package followed by the node position
+func formatCompactNode(node Node, doc *Document) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", node.GetAstNode()), "*ast.")
+	return name + " " + formatPosition(node, doc)
+}
+
+// formatPosition returns 1-based line:col-line:col span of the element in the document or pos-end token positions
+func formatPosition(elmt Element, doc *Document) string {
+	if doc == nil {
+		return fmt.Sprintf("%d-%d", elmt.GetPos(), elmt.GetEnd())
+	}
+
+	span := doc.Text.GetLinePositionSpan(doc.GetSpan(elmt), text.CharacterUnitByte)
+	return fmt.Sprintf("%d:%d-%d:%d", span.Start.Line+1, span.Start.Character+1, span.End.Line+1, span.End.Character+1)
+}
@@ -0,0 +1,115 @@
+package syntax_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+const printSrc = "package a\n\nfunc f() {\n\tprintln(1)\n}\n"
+
+func printWithOptions(t *testing.T, options syntax.PrintOptions) string {
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", printSrc)
+	assert.NoError(t, err)
+
+	var buffer bytes.Buffer
+	if options.Document == nil {
+		options.Document = doc
+	}
+	node := doc.FindNode(text.NewTextSpan(30, 0))
+	syntax.PrintWithOptions(&buffer, node, options)
+	return buffer.String()
+}
+
+func TestPrintWithOptions(t *testing.T) {
+	expected := "" +
+		"CallExpr 4:2-4:12\n" +
+		"  Ident 4:2-4:9\n" +
+		"    token IDENT \"println\" 4:2-4:9\n" +
+		"  token ( \"(\" 4:9-4:10\n" +
+		"  BasicLit 4:10-4:11\n" +
+		"    token INT \"1\" 4:10-4:11\n" +
+		"  token ) \")\" 4:11-4:12\n"
+	assert.Equal(t, expected, printWithOptions(t, syntax.PrintOptions{Format: syntax.PrintFormatCompact}))
+
+	expected = "" +
+		"CallExpr 4:2-4:12\n" +
+		"  Ident 4:2-4:9 ...\n" +
+		"  token ( \"(\" 4:9-4:10\n" +
+		"  BasicLit 4:10-4:11 ...\n" +
+		"  token ) \")\" 4:11-4:12\n"
+	assert.Equal(t, expected, printWithOptions(t, syntax.PrintOptions{Format: syntax.PrintFormatCompact, MaxDepth: 1}))
+
+	expected = "" +
+		"node *ast.CallExpr\n" +
+		"parent *ast.ExprStmt\n" +
+		"elmnts: [\n" +
+		"\tnode *ast.Ident\n" +
+		"\tparent *ast.CallExpr\n" +
+		"\telmnts: [...]\n" +
+		"\n" +
+		"\ttoken ( (\n" +
+		"\n" +
+		"\tnode *ast.BasicLit\n" +
+		"\tparent *ast.CallExpr\n" +
+		"\telmnts: [...]\n" +
+		"\n" +
+		"\ttoken ) )\n" +
+		"]\n"
+	assert.Equal(t, expected, printWithOptions(t, syntax.PrintOptions{MaxDepth: 1}))
+}
+
+func TestPrintPathTo(t *testing.T) {
+	doc, err := syntax.NewDocumentRegistry().AddDocument("a.go", printSrc)
+	assert.NoError(t, err)
+
+	node := doc.FindNode(text.NewTextSpan(31, 0))
+	printPathTo := func(options syntax.PrintOptions) string {
+		var buffer bytes.Buffer
+		syntax.PrintPathTo(&buffer, node, options)
+		return buffer.String()
+	}
+
+	path := "" +
+		"File 1:1-5:2\n" +
+		"  FuncDecl 3:1-5:2\n" +
+		"    BlockStmt 3:10-5:2\n" +
+		"      ExprStmt 4:2-4:12\n" +
+		"        CallExpr 4:2-4:12\n"
+	expected := path + "          BasicLit 4:10-4:11 ...\n"
+	assert.Equal(t, expected, printPathTo(syntax.PrintOptions{Format: syntax.PrintFormatCompact, Document: doc}))
+
+	expected = path +
+		"          BasicLit 4:10-4:11\n" +
+		"            token INT \"1\" 4:10-4:11\n"
+	assert.Equal(t, expected, printPathTo(syntax.PrintOptions{Format: syntax.PrintFormatCompact, MaxDepth: 1, Document: doc}))
+
+	expected = "" +
+		"node *ast.File\n" +
+		"parent <nil>\n" +
+		"elmnts: [\n" +
+		"\tnode *ast.FuncDecl\n" +
+		"\tparent *ast.File\n" +
+		"\telmnts: [\n" +
+		"\t\tnode *ast.BlockStmt\n" +
+		"\t\tparent *ast.FuncDecl\n" +
+		"\t\telmnts: [\n" +
+		"\t\t\tnode *ast.ExprStmt\n" +
+		"\t\t\tparent *ast.BlockStmt\n" +
+		"\t\t\telmnts: [\n" +
+		"\t\t\t\tnode *ast.CallExpr\n" +
+		"\t\t\t\tparent *ast.ExprStmt\n" +
+		"\t\t\t\telmnts: [\n" +
+		"\t\t\t\t\tnode *ast.BasicLit\n" +
+		"\t\t\t\t\tparent *ast.CallExpr\n" +
+		"\t\t\t\t\telmnts: [...]\n" +
+		"\t\t\t\t]\n" +
+		"\t\t\t]\n" +
+		"\t\t]\n" +
+		"\t]\n" +
+		"]\n"
+	assert.Equal(t, expected, printPathTo(syntax.PrintOptions{Document: doc}))
+}
@@ -0,0 +1,37 @@
+package syntax_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/syntax"
+	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
+)
+
+func checkSyntaxInvariants(t *testing.T, src string) {
+	t.Helper()
+	doc, err := syntax.NewDocumentRegistry().AddDocument("source.go", src)
+	if err != nil {
+		t.Fatalf("source does not parse: %v\nsource:\n%s", err, src)
+	}
+
+	errors := append(asttest.CheckInvariants(doc), asttest.CheckConstructionPaths(doc)...)
+	for _, err := range errors {
+		t.Error(err)
+	}
+	if len(errors) > 0 {
+		t.Logf("source:\n%s", src)
+	}
+}
+
+func TestSyntaxInvariantsOfGeneratedPrograms(t *testing.T) {
+	count := int64(500)
+	if testing.Short() {
+		count = 50
+	}
+	for seed := int64(0); seed < count; seed++ {
+		checkSyntaxInvariants(t, asttest.GenerateProgram(seed))
+		if t.Failed() {
+			t.Fatalf("invariants are broken for seed %d", seed)
+		}
+	}
+}
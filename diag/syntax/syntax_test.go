@@ -6,8 +6,8 @@ import (
 	"go/token"
 	"testing"
 
+	"github.com/a6cexz/goanalyzer/diag/syntax"
 	"github.com/stretchr/testify/assert"
-	"maxmapps.com/goanalyzer/diag/syntax"
 )
 
 func printSyntaxTree(node ast.Node) {
@@ -461,7 +461,7 @@ elmnts: [
 	node *ast.FieldList
 	parent *ast.StructType
 	elmnts: [
-		token ( (
+		token { {
 	
 		node *ast.Field
 		parent *ast.FieldList
@@ -479,7 +479,7 @@ elmnts: [
 			]
 		]
 	
-		token ) )
+		token } }
 	]
 ]
 `
@@ -562,7 +562,7 @@ elmnts: [
 	node *ast.FieldList
 	parent *ast.InterfaceType
 	elmnts: [
-		token ( (
+		token { {
 	
 		node *ast.Field
 		parent *ast.FieldList
@@ -580,7 +580,7 @@ elmnts: [
 			]
 		]
 	
-		token ) )
+		token } }
 	]
 ]
 `
@@ -1276,3 +1276,30 @@ elmnts: [
 	}
 	checkSyntaxTree(t, e, n)
 }
+
+func TestFileNode(t *testing.T) {
+	e := `node *ast.File
+parent <nil>
+elmnts: [
+	token package package
+
+	node *ast.Ident
+	parent *ast.File
+	elmnts: [
+		token main IDENT
+	]
+
+	node *ast.BadDecl
+	parent *ast.File
+	elmnts: []
+]
+`
+	n := &ast.File{
+		Package: token.Pos(1),
+		Name:    getIdent("main"),
+		Decls: []ast.Decl{
+			&ast.BadDecl{From: token.Pos(2), To: token.Pos(3)},
+		},
+	}
+	checkSyntaxTree(t, e, n)
+}
@@ -1,6 +1,7 @@
 package syntaxkind
 
 import (
+	"fmt"
 	"go/ast"
 )
 
@@ -67,6 +68,84 @@ const (
 	AstPackage
 )
 
+// astKindNames are names of ast kinds, they are names of go/ast types
+var astKindNames = [...]string{
+	AstNone:           "None",
+	AstComment:        "Comment",
+	AstCommentGroup:   "CommentGroup",
+	AstField:          "Field",
+	AstFieldList:      "FieldList",
+	AstBadExpr:        "BadExpr",
+	AstIdent:          "Ident",
+	AstEllipsis:       "Ellipsis",
+	AstBasicLit:       "BasicLit",
+	AstFuncLit:        "FuncLit",
+	AstCompositeLit:   "CompositeLit",
+	AstParenExpr:      "ParenExpr",
+	AstSelectorExpr:   "SelectorExpr",
+	AstIndexExpr:      "IndexExpr",
+	AstSliceExpr:      "SliceExpr",
+	AstTypeAssertExpr: "TypeAssertExpr",
+	AstCallExpr:       "CallExpr",
+	AstStarExpr:       "StarExpr",
+	AstUnaryExpr:      "UnaryExpr",
+	AstBinaryExpr:     "BinaryExpr",
+	AstKeyValueExpr:   "KeyValueExpr",
+	AstArrayType:      "ArrayType",
+	AstStructType:     "StructType",
+	AstFuncType:       "FuncType",
+	AstInterfaceType:  "InterfaceType",
+	AstMapType:        "MapType",
+	AstChanType:       "ChanType",
+	AstBadStmt:        "BadStmt",
+	AstDeclStmt:       "DeclStmt",
+	AstEmptyStmt:      "EmptyStmt",
+	AstLabeledStmt:    "LabeledStmt",
+	AstExprStmt:       "ExprStmt",
+	AstSendStmt:       "SendStmt",
+	AstIncDecStmt:     "IncDecStmt",
+	AstAssignStmt:     "AssignStmt",
+	AstGoStmt:         "GoStmt",
+	AstDeferStmt:      "DeferStmt",
+	AstReturnStmt:     "ReturnStmt",
+	AstBranchStmt:     "BranchStmt",
+	AstBlockStmt:      "BlockStmt",
+	AstIfStmt:         "IfStmt",
+	AstCaseClause:     "CaseClause",
+	AstSwitchStmt:     "SwitchStmt",
+	AstTypeSwitchStmt: "TypeSwitchStmt",
+	AstCommClause:     "CommClause",
+	AstSelectStmt:     "SelectStmt",
+	AstForStmt:        "ForStmt",
+	AstRangeStmt:      "RangeStmt",
+	AstImportSpec:     "ImportSpec",
+	AstValueSpec:      "ValueSpec",
+	AstTypeSpec:       "TypeSpec",
+	AstBadDecl:        "BadDecl",
+	AstGenDecl:        "GenDecl",
+	AstFuncDecl:       "FuncDecl",
+	AstFile:           "File",
+	AstPackage:        "Package",
+}
+
+// String returns name of the ast kind, e.g. "CallExpr"
+func (k AstKind) String() string {
+	if k < 0 || int(k) >= len(astKindNames) {
+		return fmt.Sprintf("AstKind(%d)", int(k))
+	}
+	return astKindNames[k]
+}
+
+// GetAstKindByName returns ast kind by its name, e.g. "CallExpr"
+func GetAstKindByName(name string) (AstKind, bool) {
+	for k, n := range astKindNames {
+		if n == name && AstKind(k) != AstNone {
+			return AstKind(k), true
+		}
+	}
+	return AstNone, false
+}
+
 // GetAstKind return ast node kind
 func GetAstKind(node ast.Node) AstKind {
 	switch node.(type) {
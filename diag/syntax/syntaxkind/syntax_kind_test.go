@@ -3,6 +3,7 @@ package syntaxkind_test
 import (
 	"fmt"
 	"go/ast"
+	"strings"
 	"testing"
 
 	"github.com/a6cexz/goanalyzer/diag/syntax/asttest"
@@ -70,8 +71,8 @@ var (
 )
 
 func checkNodeAstKinds(t *testing.T, src string) {
-	_, file, _, err := asttest.ParseTestFile(src)
-	assert.NoError(t, err)
+	_, file, _, diags := asttest.ParseTestFile(src)
+	assert.Empty(t, diags)
 
 	nodes := syntaxkind.CollectAllNodes(file)
 	for _, node := range nodes {
@@ -124,3 +125,22 @@ func (s *SomeStruct) Func1() int {
 }`
 	checkNodeAstKinds(t, src)
 }
+
+func TestAstKindNames(t *testing.T) {
+	for name, kind := range TypeAstKindMap {
+		kindName := strings.TrimPrefix(name, "*ast.")
+		assert.Equal(t, kindName, kind.String())
+
+		actual, ok := syntaxkind.GetAstKindByName(kindName)
+		assert.True(t, ok)
+		assert.Equal(t, kind, actual)
+	}
+
+	assert.Equal(t, "None", syntaxkind.AstNone.String())
+	assert.Equal(t, "AstKind(100)", syntaxkind.AstKind(100).String())
+
+	_, ok := syntaxkind.GetAstKindByName("None")
+	assert.False(t, ok)
+	_, ok = syntaxkind.GetAstKindByName("callexpr")
+	assert.False(t, ok)
+}
@@ -0,0 +1,5 @@
+package main
+
+func main() {
+	[|fmt.Println("a", b...)|]
+}
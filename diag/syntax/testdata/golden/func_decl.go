@@ -0,0 +1,9 @@
+package main
+
+// Sum returns sum of the values
+func (s *Set) Sum(values ...int) (r int) {
+	for _, v := range values {
+		r += v
+	}
+	return
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"fmt"
+	str "strings"
+)
+
+type (
+	A = int
+	B struct {
+		a, b int `json:"a"`
+	}
+)
+
+const c, d = 1, "d"
@@ -0,0 +1,10 @@
+package main
+
+func f(x int) int {
+	$$if y := x * 2; y > 10 {
+		return y
+	} else if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,3 @@
+package main
+
+var [|a int = 1|]
@@ -0,0 +1,170 @@
+package text
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffRegion is the range of changed old lines and the range of new lines which replaced them, ends are exclusive
+type diffRegion struct {
+	oldStart int
+	oldEnd   int
+	newStart int
+	newEnd   int
+}
+
+// GetUnifiedDiff returns unified diff of the old and the new text with the given number of context lines,
+// the diff is empty if the texts are equal. Changed lines are found from change ranges of the new text,
+// so the diff is precise if the new text was produced from the old text by WithChanges.
+func GetUnifiedDiff(oldName, newName string, old, new *SourceText, context int) string {
+	if context < 0 {
+		panic("context is negative!")
+	}
+
+	oldLines := splitLines(old.String())
+	newLines := splitLines(new.String())
+	regions := getDiffRegions(old, new, oldLines, newLines)
+	if len(regions) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(regions); {
+		j := i + 1
+		for j < len(regions) && regions[j].oldStart-regions[j-1].oldEnd <= 2*context {
+			j++
+		}
+		writeHunk(&b, oldLines, newLines, regions[i:j], context)
+		i = j
+	}
+	return b.String()
+}
+
+// getDiffRegions returns line regions of the change ranges. Changes on the same line are merged
+// and lines which are equal at the start and at the end of the region are trimmed.
+func getDiffRegions(old, new *SourceText, oldLines, newLines []string) []diffRegion {
+	oldStarts := getSplitLineStarts(oldLines)
+	newStarts := getSplitLineStarts(newLines)
+
+	regions := []diffRegion{}
+	delta := 0
+	for _, r := range new.GetChangeRanges(old) {
+		newStart := r.Span.Start() + delta
+		delta += r.NewLength - r.Span.Length()
+
+		region := diffRegion{}
+		region.oldStart, region.oldEnd = getLineRange(oldStarts, len(oldLines), r.Span.Start(), r.Span.End())
+		region.newStart, region.newEnd = getLineRange(newStarts, len(newLines), newStart, newStart+r.NewLength)
+
+		if n := len(regions); n > 0 && region.oldStart < regions[n-1].oldEnd {
+			regions[n-1].oldEnd = region.oldEnd
+			regions[n-1].newEnd = region.newEnd
+			continue
+		}
+		regions = append(regions, region)
+	}
+
+	trimmed := []diffRegion{}
+	for _, r := range regions {
+		for r.oldStart < r.oldEnd && r.newStart < r.newEnd && oldLines[r.oldStart] == newLines[r.newStart] {
+			r.oldStart++
+			r.newStart++
+		}
+		for r.oldStart < r.oldEnd && r.newStart < r.newEnd && oldLines[r.oldEnd-1] == newLines[r.newEnd-1] {
+			r.oldEnd--
+			r.newEnd--
+		}
+		if r.oldStart < r.oldEnd || r.newStart < r.newEnd {
+			trimmed = append(trimmed, r)
+		}
+	}
+	return trimmed
+}
+
+// getLineRange returns range of lines which contain the span from start to end
+func getLineRange(starts []int, count int, start, end int) (int, int) {
+	first := sort.SearchInts(starts, start+1) - 1
+	last := sort.SearchInts(starts, end+1) - 1
+	if first > count {
+		first = count
+	}
+	if last >= count {
+		last = count - 1
+	}
+	if last < first {
+		return first, first
+	}
+	return first, last + 1
+}
+
+func writeHunk(b *strings.Builder, oldLines, newLines []string, regions []diffRegion, context int) {
+	first := regions[0]
+	last := regions[len(regions)-1]
+	oldStart := first.oldStart - context
+	if oldStart < 0 {
+		oldStart = 0
+	}
+	oldEnd := last.oldEnd + context
+	if oldEnd > len(oldLines) {
+		oldEnd = len(oldLines)
+	}
+	newStart := first.newStart - (first.oldStart - oldStart)
+	newEnd := last.newEnd + (oldEnd - last.oldEnd)
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", formatHunkRange(oldStart, oldEnd-oldStart), formatHunkRange(newStart, newEnd-newStart))
+	pos := oldStart
+	for _, r := range regions {
+		writeDiffLines(b, " ", oldLines[pos:r.oldStart])
+		writeDiffLines(b, "-", oldLines[r.oldStart:r.oldEnd])
+		writeDiffLines(b, "+", newLines[r.newStart:r.newEnd])
+		pos = r.oldEnd
+	}
+	writeDiffLines(b, " ", oldLines[pos:oldEnd])
+}
+
+// formatHunkRange returns 1-based hunk range, empty range refers to the line before it
+func formatHunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+func writeDiffLines(b *strings.Builder, prefix string, lines []string) {
+	for _, line := range lines {
+		b.WriteString(prefix)
+		b.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// splitLines splits the string after line feeds, the last line has no line feed if the string does not end with it
+func splitLines(str string) []string {
+	lines := strings.SplitAfter(str, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// getSplitLineStarts returns start offsets of the lines, the text end is the start of an empty last line
+// if the text is empty or ends with a line feed
+func getSplitLineStarts(lines []string) []int {
+	starts := []int{}
+	pos := 0
+	for _, line := range lines {
+		starts = append(starts, pos)
+		pos += len(line)
+	}
+	if len(lines) == 0 || strings.HasSuffix(lines[len(lines)-1], "\n") {
+		starts = append(starts, pos)
+	}
+	return starts
+}
@@ -0,0 +1,190 @@
+package text_test
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func getDiff(t *testing.T, src string, changes ...text.TextChange) string {
+	old := text.NewSourceText(src)
+	new, err := old.WithChanges(changes...)
+	assert.NoError(t, err)
+	return text.GetUnifiedDiff("a.go", "a.go", old, new, 1)
+}
+
+func Test_GetUnifiedDiff(t *testing.T) {
+	src := "a\nb\nc\nd\ne\nf\ng\n"
+	assert.Equal(t, "", getDiff(t, src))
+	assert.Equal(t, "", getDiff(t, src, text.NewTextChange(text.NewTextSpan(2, 1), "b")))
+
+	expected := "" +
+		"--- a.go\n" +
+		"+++ a.go\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" a\n" +
+		"-b\n" +
+		"+x\n" +
+		" c\n" +
+		"@@ -5,3 +5,4 @@\n" +
+		" e\n" +
+		"-f\n" +
+		"+y\n" +
+		"+z\n" +
+		" g\n"
+	assert.Equal(t, expected, getDiff(t, src,
+		text.NewTextChange(text.NewTextSpan(2, 1), "x"),
+		text.NewTextChange(text.NewTextSpan(10, 1), "y\nz")))
+
+	expected = "" +
+		"--- a.go\n" +
+		"+++ a.go\n" +
+		"@@ -1,4 +1,3 @@\n" +
+		" a\n" +
+		"-b\n" +
+		"-c\n" +
+		"+bc\n" +
+		" d\n"
+	assert.Equal(t, expected, getDiff(t, src,
+		text.NewTextChange(text.NewTextSpan(3, 1), ""),
+		text.NewTextChange(text.NewTextSpan(4, 0), "")))
+
+	expected = "" +
+		"--- a.go\n" +
+		"+++ a.go\n" +
+		"@@ -3,2 +2,0 @@\n" +
+		"-c\n" +
+		"-d\n"
+	old := text.NewSourceText(src)
+	new, _ := old.WithChanges(text.NewTextChange(text.NewTextSpan(4, 4), ""))
+	assert.Equal(t, expected, text.GetUnifiedDiff("a.go", "a.go", old, new, 0))
+}
+
+func Test_GetUnifiedDiff_TextEnd(t *testing.T) {
+	expected := "" +
+		"--- a.go\n" +
+		"+++ a.go\n" +
+		"@@ -1 +1,2 @@\n" +
+		" a\n" +
+		"+b\n"
+	assert.Equal(t, expected, getDiff(t, "a\n", text.NewTextChange(text.NewTextSpan(2, 0), "b\n")))
+
+	expected = "" +
+		"--- a.go\n" +
+		"+++ a.go\n" +
+		"@@ -1 +1,2 @@\n" +
+		"-a\n" +
+		"\\ No newline at end of file\n" +
+		"+a\n" +
+		"+b\n" +
+		"\\ No newline at end of file\n"
+	assert.Equal(t, expected, getDiff(t, "a", text.NewTextChange(text.NewTextSpan(1, 0), "\nb")))
+
+	expected = "" +
+		"--- a.go\n" +
+		"+++ a.go\n" +
+		"@@ -0,0 +1 @@\n" +
+		"+a\n"
+	assert.Equal(t, expected, getDiff(t, "", text.NewTextChange(text.NewTextSpan(0, 0), "a\n")))
+}
+
+func Test_GetUnifiedDiff_Unrelated(t *testing.T) {
+	old := text.NewSourceText("a\nb\nc\n")
+	new := text.NewSourceText("a\nx\nc\n")
+	expected := "" +
+		"--- old\n" +
+		"+++ new\n" +
+		"@@ -2 +2 @@\n" +
+		"-b\n" +
+		"+x\n"
+	assert.Equal(t, expected, text.GetUnifiedDiff("old", "new", old, new, 0))
+	assert.Panics(t, func() { text.GetUnifiedDiff("old", "new", old, new, -1) })
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@$`)
+
+// applyDiff applies unified diff produced by GetUnifiedDiff to the old text
+func applyDiff(t *testing.T, old string, diff string) string {
+	lines := strings.SplitAfter(old, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	result := []string{}
+	pos := 0
+	diffLines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")[2:]
+	for i := 0; i < len(diffLines); i++ {
+		line := diffLines[i]
+		if m := hunkHeader.FindStringSubmatch(line); m != nil {
+			start, _ := strconv.Atoi(m[1])
+			if m[2] != "0" {
+				start--
+			}
+			result = append(result, lines[pos:start]...)
+			pos = start
+			continue
+		}
+
+		content := line[1:] + "\n"
+		if i+1 < len(diffLines) && strings.HasPrefix(diffLines[i+1], "\\") {
+			content = line[1:]
+			i++
+		}
+		switch line[0] {
+		case ' ':
+			assert.Equal(t, lines[pos], content)
+			result = append(result, content)
+			pos++
+		case '-':
+			assert.Equal(t, lines[pos], content)
+			pos++
+		case '+':
+			result = append(result, content)
+		default:
+			t.Fatalf("invalid diff line %q", line)
+		}
+	}
+	return strings.Join(append(result, lines[pos:]...), "")
+}
+
+func Test_GetUnifiedDiff_Random(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	pieces := []string{"", "a", "b", "\n", "a\n", "\nb\n"}
+	random := func() string {
+		var b strings.Builder
+		for n := r.Intn(6); n > 0; n-- {
+			b.WriteString(pieces[r.Intn(len(pieces))])
+		}
+		return b.String()
+	}
+
+	for i := 0; i < 2000; i++ {
+		old := text.NewSourceText(random() + random() + random())
+		changes := []text.TextChange{}
+		pos := 0
+		for pos < old.Length() && r.Intn(3) > 0 {
+			start := pos + r.Intn(old.Length()-pos+1)
+			end := start + r.Intn(old.Length()-start+1)
+			changes = append(changes, text.NewTextChange(text.NewTextSpanFromBounds(start, end), random()))
+			pos = end + 1
+		}
+
+		new, err := old.WithChanges(changes...)
+		assert.NoError(t, err)
+		diff := text.GetUnifiedDiff("a", "b", old, new, r.Intn(3))
+		name := fmt.Sprintf("%q %v", old.String(), changes)
+		if old.String() == new.String() {
+			assert.Equal(t, "", diff, name)
+			continue
+		}
+		if !assert.Equal(t, new.String(), applyDiff(t, old.String(), diff), name) {
+			return
+		}
+	}
+}
@@ -24,3 +24,10 @@ var c = 1234
 	assert.Equal(t, 33, m["#tag_2a#"])
 	assert.Equal(t, 46, m["#tag_3b#"])
 }
+
+func TestGetTextMatchesUnicode(t *testing.T) {
+	src := `var s = "日本"#tag_1#`
+	s, m := helpers.RemoveTextMarkers(src)
+	assert.Equal(t, `var s = "日本"`, s)
+	assert.Equal(t, 16, m["#tag_1#"])
+}
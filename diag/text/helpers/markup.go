@@ -0,0 +1,226 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// Markup span names
+const (
+	// CaretName is the name of $$ caret positions, caret is stored as an empty span
+	CaretName = "$$"
+	// AnonymousName is the name of [|...|] spans
+	AnonymousName = ""
+)
+
+const (
+	caretMarker         = "$$"
+	anonymousOpenMarker = "[|"
+	anonymousEndMarker  = "|]"
+	namedOpenMarker     = "{|"
+	namedEndMarker      = "|}"
+	nameSeparator       = ":"
+)
+
+// MarkupError is returned when markup is malformed
+type MarkupError struct {
+	Offset  int
+	Message string
+}
+
+func (e *MarkupError) Error() string {
+	return fmt.Sprintf("markup error at offset %d: %s", e.Offset, e.Message)
+}
+
+type markupSpanStart struct {
+	name   string
+	start  int
+	offset int
+}
+
+// ParseMarkup removes markup from the given string and returns new string and map of spans by name.
+// Supported markup: $$ caret, [|...|] anonymous spans and {|name:...|} named spans. Spans can be nested
+// and the same name can be used several times. Span offsets are byte offsets in the returned string.
+func ParseMarkup(str string) (string, map[string][]text.TextSpan, error) {
+	var b strings.Builder
+	m := map[string][]text.TextSpan{}
+	stack := []markupSpanStart{}
+
+	for i := 0; i < len(str); {
+		rest := str[i:]
+		switch {
+		case strings.HasPrefix(rest, caretMarker):
+			m[CaretName] = append(m[CaretName], text.NewTextSpan(b.Len(), 0))
+			i += len(caretMarker)
+
+		case strings.HasPrefix(rest, anonymousOpenMarker):
+			stack = append(stack, markupSpanStart{name: AnonymousName, start: b.Len(), offset: i})
+			i += len(anonymousOpenMarker)
+
+		case strings.HasPrefix(rest, namedOpenMarker):
+			end := strings.Index(rest, nameSeparator)
+			if end < 0 {
+				return "", nil, &MarkupError{Offset: i, Message: "missing ':' after span name"}
+			}
+			name := rest[len(namedOpenMarker):end]
+			if !isValidMarkupName(name) {
+				return "", nil, &MarkupError{Offset: i, Message: fmt.Sprintf("invalid span name %q", name)}
+			}
+			stack = append(stack, markupSpanStart{name: name, start: b.Len(), offset: i})
+			i += end + len(nameSeparator)
+
+		case strings.HasPrefix(rest, anonymousEndMarker), strings.HasPrefix(rest, namedEndMarker):
+			anonymous := strings.HasPrefix(rest, anonymousEndMarker)
+			if len(stack) == 0 {
+				return "", nil, &MarkupError{Offset: i, Message: fmt.Sprintf("unexpected %q", rest[:2])}
+			}
+			top := stack[len(stack)-1]
+			if anonymous != (top.name == AnonymousName) {
+				return "", nil, &MarkupError{Offset: i, Message: fmt.Sprintf("%q does not match span opened at offset %d", rest[:2], top.offset)}
+			}
+			stack = stack[:len(stack)-1]
+			m[top.name] = append(m[top.name], text.NewTextSpanFromBounds(top.start, b.Len()))
+			i += 2
+
+		default:
+			b.WriteByte(str[i])
+			i++
+		}
+	}
+
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return "", nil, &MarkupError{Offset: top.offset, Message: "span is not closed"}
+	}
+
+	for _, spans := range m {
+		sort.SliceStable(spans, func(i, j int) bool {
+			return spans[i].CompareTo(spans[j]) < 0
+		})
+	}
+	return b.String(), m, nil
+}
+
+// MustParseMarkup is like ParseMarkup but panics if markup is malformed
+func MustParseMarkup(str string) (string, map[string][]text.TextSpan) {
+	s, m, err := ParseMarkup(str)
+	if err != nil {
+		panic(err)
+	}
+	return s, m
+}
+
+type markupSpan struct {
+	name string
+	span text.TextSpan
+}
+
+// RenderMarkup is the inverse of ParseMarkup: it inserts markup for the given spans into the string.
+// Empty spans named CaretName are rendered as $$, spans named AnonymousName as [|...|] and other spans
+// as {|name:...|}. Spans must be inside the string and must not cross each other.
+func RenderMarkup(str string, spans map[string][]text.TextSpan) (string, error) {
+	opens := []markupSpan{}
+	empties := []markupSpan{}
+	for name, list := range spans {
+		if name != AnonymousName && !isValidMarkupName(name) {
+			return "", fmt.Errorf("invalid span name %q", name)
+		}
+		for _, span := range list {
+			if !span.IsValid() || span.End() > len(str) {
+				return "", fmt.Errorf("span %v of %q is outside of the text", span, name)
+			}
+			s := markupSpan{name: name, span: span}
+			if span.IsEmpty() {
+				empties = append(empties, s)
+			} else {
+				opens = append(opens, s)
+			}
+		}
+	}
+
+	sort.Slice(opens, func(i, j int) bool {
+		a, b := opens[i].span, opens[j].span
+		if a.Start() != b.Start() {
+			return a.Start() < b.Start()
+		}
+		if a.End() != b.End() {
+			return a.End() > b.End()
+		}
+		return opens[i].name < opens[j].name
+	})
+	sort.Slice(empties, func(i, j int) bool {
+		if empties[i].span.Start() != empties[j].span.Start() {
+			return empties[i].span.Start() < empties[j].span.Start()
+		}
+		return empties[i].name < empties[j].name
+	})
+
+	var b strings.Builder
+	stack := []markupSpan{}
+	for pos := 0; pos <= len(str); pos++ {
+		for len(stack) > 0 && stack[len(stack)-1].span.End() == pos {
+			writeMarkupEnd(&b, stack[len(stack)-1])
+			stack = stack[:len(stack)-1]
+		}
+
+		for len(empties) > 0 && empties[0].span.Start() == pos {
+			writeMarkupStart(&b, empties[0])
+			writeMarkupEnd(&b, empties[0])
+			empties = empties[1:]
+		}
+
+		for len(opens) > 0 && opens[0].span.Start() == pos {
+			s := opens[0]
+			if len(stack) > 0 && stack[len(stack)-1].span.End() < s.span.End() {
+				top := stack[len(stack)-1]
+				return "", fmt.Errorf("span %v of %q crosses span %v of %q", s.span, s.name, top.span, top.name)
+			}
+			writeMarkupStart(&b, s)
+			stack = append(stack, s)
+			opens = opens[1:]
+		}
+
+		if pos < len(str) {
+			b.WriteByte(str[pos])
+		}
+	}
+	return b.String(), nil
+}
+
+func writeMarkupStart(b *strings.Builder, s markupSpan) {
+	switch {
+	case s.name == CaretName && s.span.IsEmpty():
+		b.WriteString(caretMarker)
+	case s.name == AnonymousName:
+		b.WriteString(anonymousOpenMarker)
+	default:
+		b.WriteString(namedOpenMarker)
+		b.WriteString(s.name)
+		b.WriteString(nameSeparator)
+	}
+}
+
+func writeMarkupEnd(b *strings.Builder, s markupSpan) {
+	switch {
+	case s.name == CaretName && s.span.IsEmpty():
+	case s.name == AnonymousName:
+		b.WriteString(anonymousEndMarker)
+	default:
+		b.WriteString(namedEndMarker)
+	}
+}
+
+func isValidMarkupName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r == '|' || r == '{' || r == '}' || r == ':' || r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package helpers_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/a6cexz/goanalyzer/diag/text/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMarkup(t *testing.T) {
+	src := `package main
+var a = [|1|]0
+var b = {|lit:2$$0|}
+var c = {|lit:"日本"|} + [|{|id:x|}|]
+`
+	e := `package main
+var a = 10
+var b = 20
+var c = "日本" + x
+`
+	s, m, err := helpers.ParseMarkup(src)
+	assert.NoError(t, err)
+	assert.Equal(t, e, s)
+
+	assert.Equal(t, []text.TextSpan{text.NewTextSpan(33, 0)}, m[helpers.CaretName])
+	assert.Equal(t, []text.TextSpan{text.NewTextSpan(32, 2), text.NewTextSpan(43, 8)}, m["lit"])
+	assert.Equal(t, `"日本"`, s[43:51])
+	assert.Equal(t, []text.TextSpan{text.NewTextSpan(21, 1), text.NewTextSpan(54, 1)}, m[helpers.AnonymousName])
+	assert.Equal(t, []text.TextSpan{text.NewTextSpan(54, 1)}, m["id"])
+	assert.Nil(t, m["none"])
+}
+
+func TestParseMarkupErrors(t *testing.T) {
+	cases := []string{
+		"a [|b",
+		"a |]b",
+		"a {|n:b|]",
+		"a [|b|}",
+		"a {|b",
+		"a {|n n:b|}",
+		"a {|:b|}",
+	}
+	for _, c := range cases {
+		_, _, err := helpers.ParseMarkup(c)
+		assert.IsType(t, &helpers.MarkupError{}, err, c)
+	}
+
+	assert.Panics(t, func() { helpers.MustParseMarkup("[|") })
+}
+
+func TestRenderMarkup(t *testing.T) {
+	src := `package main
+var a = [|1|]0
+var b = {|lit:2$$0|}
+var c = {|lit:"日本"|} + [|{|id:x|}|]{|e:|}
+`
+	s, m, err := helpers.ParseMarkup(src)
+	assert.NoError(t, err)
+
+	r, err := helpers.RenderMarkup(s, m)
+	assert.NoError(t, err)
+	assert.Equal(t, src, r)
+
+	r, err = helpers.RenderMarkup("abcd", map[string][]text.TextSpan{
+		"x":                   {text.NewTextSpan(0, 4), text.NewTextSpan(1, 1)},
+		helpers.AnonymousName: {text.NewTextSpan(0, 4), text.NewTextSpan(1, 0)},
+		helpers.CaretName:     {text.NewTextSpan(4, 0)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "[|{|x:a[||]{|x:b|}cd|}|]$$", r)
+}
+
+func TestRenderMarkupErrors(t *testing.T) {
+	_, err := helpers.RenderMarkup("abcd", map[string][]text.TextSpan{
+		"x": {text.NewTextSpan(0, 2)},
+		"y": {text.NewTextSpan(1, 2)},
+	})
+	assert.Error(t, err)
+
+	_, err = helpers.RenderMarkup("abcd", map[string][]text.TextSpan{
+		"x": {text.NewTextSpan(3, 2)},
+	})
+	assert.Error(t, err)
+
+	_, err = helpers.RenderMarkup("abcd", map[string][]text.TextSpan{
+		"x y": {text.NewTextSpan(0, 2)},
+	})
+	assert.Error(t, err)
+}
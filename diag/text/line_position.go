@@ -0,0 +1,81 @@
+package text
+
+import (
+	"fmt"
+)
+
+// CharacterUnit denotes units used to count characters in a line
+type CharacterUnit int
+
+// Character units
+const (
+	CharacterUnitByte CharacterUnit = iota
+	CharacterUnitRune
+	CharacterUnitUTF16
+)
+
+// String returns string representation of the character unit
+func (u CharacterUnit) String() string {
+	switch u {
+	case CharacterUnitByte:
+		return "byte"
+	case CharacterUnitRune:
+		return "rune"
+	case CharacterUnitUTF16:
+		return "utf-16"
+	}
+	return "none"
+}
+
+// LinePosition represents zero based line and character position in text
+type LinePosition struct {
+	Line      int
+	Character int
+}
+
+// NewLinePosition creates new line position
+func NewLinePosition(line, character int) LinePosition {
+	if line < 0 {
+		panic("line is negative!")
+	}
+
+	if character < 0 {
+		panic("character is negative!")
+	}
+
+	return LinePosition{Line: line, Character: character}
+}
+
+// String returns string representation of the line position
+func (p LinePosition) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Character)
+}
+
+// CompareTo compares line positions
+func (p LinePosition) CompareTo(pos LinePosition) int {
+	r := p.Line - pos.Line
+	if r != 0 {
+		return r
+	}
+	return p.Character - pos.Character
+}
+
+// LinePositionSpan represents span in text defined by line positions
+type LinePositionSpan struct {
+	Start LinePosition
+	End   LinePosition
+}
+
+// NewLinePositionSpan creates new line position span
+func NewLinePositionSpan(start, end LinePosition) LinePositionSpan {
+	if end.CompareTo(start) < 0 {
+		panic("end is less than start!")
+	}
+
+	return LinePositionSpan{Start: start, End: end}
+}
+
+// String returns string representation of the line position span
+func (s LinePositionSpan) String() string {
+	return fmt.Sprintf("(%v)-(%v)", s.Start, s.End)
+}
@@ -0,0 +1,40 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LinePosition_String(t *testing.T) {
+	p := text.NewLinePosition(1, 5)
+	assert.Equal(t, "1:5", p.String())
+}
+
+func Test_LinePosition_CompareTo(t *testing.T) {
+	p1 := text.NewLinePosition(1, 5)
+	p2 := text.NewLinePosition(1, 5)
+	assert.True(t, p1.CompareTo(p2) == 0)
+
+	p2 = text.NewLinePosition(1, 6)
+	assert.True(t, p1.CompareTo(p2) < 0)
+	assert.True(t, p2.CompareTo(p1) > 0)
+
+	p2 = text.NewLinePosition(2, 0)
+	assert.True(t, p1.CompareTo(p2) < 0)
+	assert.True(t, p2.CompareTo(p1) > 0)
+}
+
+func Test_LinePosition_NewLinePosition(t *testing.T) {
+	assert.Panics(t, func() { text.NewLinePosition(-1, 0) })
+	assert.Panics(t, func() { text.NewLinePosition(0, -1) })
+}
+
+func Test_LinePositionSpan_NewLinePositionSpan(t *testing.T) {
+	s := text.NewLinePositionSpan(text.NewLinePosition(0, 1), text.NewLinePosition(2, 0))
+	assert.Equal(t, "(0:1)-(2:0)", s.String())
+	assert.Panics(t, func() {
+		text.NewLinePositionSpan(text.NewLinePosition(1, 0), text.NewLinePosition(0, 1))
+	})
+}
@@ -0,0 +1,283 @@
+package text
+
+import (
+	"sort"
+	"strings"
+)
+
+// SourceText represents source text with line information
+type SourceText struct {
+	text       string
+	lineStarts []int
+	previous   *SourceText
+	changes    []TextChangeRange
+}
+
+// NewSourceText creates new source text
+func NewSourceText(str string) *SourceText {
+	return &SourceText{
+		text:       str,
+		lineStarts: getLineStarts(str),
+	}
+}
+
+// String returns source text string
+func (t *SourceText) String() string {
+	return t.text
+}
+
+// Length returns source text length in bytes
+func (t *SourceText) Length() int {
+	return len(t.text)
+}
+
+// GetSubText returns text of the given span
+func (t *SourceText) GetSubText(span TextSpan) string {
+	t.checkSpan(span)
+	return t.text[span.Start():span.End()]
+}
+
+// LineCount returns number of lines in the source text
+func (t *SourceText) LineCount() int {
+	return len(t.lineStarts)
+}
+
+// GetLineSpan returns span of the given line without line break
+func (t *SourceText) GetLineSpan(line int) TextSpan {
+	t.checkLine(line)
+	start := t.lineStarts[line]
+	end := t.getLineEnd(line)
+	return NewTextSpanFromBounds(start, end)
+}
+
+// GetLineSpanIncludingLineBreak returns span of the given line including line break
+func (t *SourceText) GetLineSpanIncludingLineBreak(line int) TextSpan {
+	t.checkLine(line)
+	start := t.lineStarts[line]
+	end := len(t.text)
+	if line+1 < len(t.lineStarts) {
+		end = t.lineStarts[line+1]
+	}
+	return NewTextSpanFromBounds(start, end)
+}
+
+// GetLineText returns text of the given line without line break
+func (t *SourceText) GetLineText(line int) string {
+	return t.GetSubText(t.GetLineSpan(line))
+}
+
+// GetLineFromPosition returns line which contains the given position
+func (t *SourceText) GetLineFromPosition(pos int) int {
+	t.checkPos(pos)
+	return sort.SearchInts(t.lineStarts, pos+1) - 1
+}
+
+// GetLinePosition converts position to line position
+func (t *SourceText) GetLinePosition(pos int, unit CharacterUnit) LinePosition {
+	line := t.GetLineFromPosition(pos)
+	start := t.lineStarts[line]
+	end := pos
+	if lineEnd := t.getLineEnd(line); end > lineEnd {
+		end = lineEnd
+	}
+	character := countCharacters(t.text[start:end], unit)
+	return NewLinePosition(line, character)
+}
+
+// GetPosition converts line position to position. Character beyond the end of the line is clamped to the line end.
+func (t *SourceText) GetPosition(pos LinePosition, unit CharacterUnit) int {
+	span := t.GetLineSpan(pos.Line)
+	offset := getCharactersLength(t.GetSubText(span), pos.Character, unit)
+	return span.Start() + offset
+}
+
+// GetLinePositionSpan converts text span to line position span
+func (t *SourceText) GetLinePositionSpan(span TextSpan, unit CharacterUnit) LinePositionSpan {
+	t.checkSpan(span)
+	start := t.GetLinePosition(span.Start(), unit)
+	end := t.GetLinePosition(span.End(), unit)
+	return NewLinePositionSpan(start, end)
+}
+
+// GetTextSpan converts line position span to text span
+func (t *SourceText) GetTextSpan(span LinePositionSpan, unit CharacterUnit) TextSpan {
+	start := t.GetPosition(span.Start, unit)
+	end := t.GetPosition(span.End, unit)
+	return NewTextSpanFromBounds(start, end)
+}
+
+// WithChanges returns new source text with the given changes applied. Changes must not overlap.
+func (t *SourceText) WithChanges(changes ...TextChange) (*SourceText, error) {
+	if err := ValidateTextChanges(changes, len(t.text)); err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		return t, nil
+	}
+
+	var b strings.Builder
+	ranges := []TextChangeRange{}
+	pos := 0
+	for _, c := range SortTextChanges(changes) {
+		b.WriteString(t.text[pos:c.Span.Start()])
+		b.WriteString(c.NewText)
+		pos = c.Span.End()
+		ranges = append(ranges, NewTextChangeRange(c.Span, len(c.NewText)))
+	}
+	b.WriteString(t.text[pos:])
+
+	r := NewSourceText(b.String())
+	r.previous = t
+	r.changes = ranges
+	return r, nil
+}
+
+// GetChangeRanges returns ranges of the old text which were changed to get this text.
+// If the text was produced from old by several WithChanges calls the ranges are collapsed into one range.
+// If the text is not derived from old the whole old text is reported as changed.
+func (t *SourceText) GetChangeRanges(old *SourceText) []TextChangeRange {
+	if old == t {
+		return []TextChangeRange{}
+	}
+
+	if t.previous == old {
+		r := make([]TextChangeRange, len(t.changes))
+		copy(r, t.changes)
+		return r
+	}
+
+	versions := []*SourceText{}
+	for v := t; v != old; v = v.previous {
+		if v == nil || v.previous == nil {
+			span := NewTextSpan(0, old.Length())
+			return []TextChangeRange{NewTextChangeRange(span, t.Length())}
+		}
+		versions = append(versions, v)
+	}
+
+	var r TextChangeRange
+	for i := len(versions) - 1; i >= 0; i-- {
+		c := collapseChangeRanges(versions[i].changes)
+		if i == len(versions)-1 {
+			r = c
+			continue
+		}
+		r = mergeChangeRanges(r, c)
+	}
+	return []TextChangeRange{r}
+}
+
+func collapseChangeRanges(ranges []TextChangeRange) TextChangeRange {
+	first := ranges[0]
+	last := ranges[len(ranges)-1]
+	span := NewTextSpanFromBounds(first.Span.Start(), last.Span.End())
+	delta := 0
+	for _, r := range ranges {
+		delta += r.NewLength - r.Span.Length()
+	}
+	return NewTextChangeRange(span, span.Length()+delta)
+}
+
+// mergeChangeRanges merges r1 (old -> middle) and r2 (middle -> new) into one range (old -> new)
+func mergeChangeRanges(r1, r2 TextChangeRange) TextChangeRange {
+	start := r1.Span.Start()
+	if r2.Span.Start() < start {
+		start = r2.Span.Start()
+	}
+
+	middleEnd := r1.Span.Start() + r1.NewLength
+	if r2.Span.End() > middleEnd {
+		middleEnd = r2.Span.End()
+	}
+
+	oldEnd := middleEnd - r1.NewLength + r1.Span.Length()
+	newEnd := middleEnd + r2.NewLength - r2.Span.Length()
+	return NewTextChangeRange(NewTextSpanFromBounds(start, oldEnd), newEnd-start)
+}
+
+func (t *SourceText) getLineEnd(line int) int {
+	if line+1 >= len(t.lineStarts) {
+		return len(t.text)
+	}
+
+	end := t.lineStarts[line+1]
+	if end > 0 && t.text[end-1] == '\n' {
+		end--
+	}
+	if end > t.lineStarts[line] && t.text[end-1] == '\r' {
+		end--
+	}
+	return end
+}
+
+func (t *SourceText) checkLine(line int) {
+	if line < 0 || line >= len(t.lineStarts) {
+		panic("line is out of range!")
+	}
+}
+
+func (t *SourceText) checkPos(pos int) {
+	if pos < 0 || pos > len(t.text) {
+		panic("position is out of range!")
+	}
+}
+
+func (t *SourceText) checkSpan(span TextSpan) {
+	if !span.IsValid() || span.End() > len(t.text) {
+		panic("span is out of range!")
+	}
+}
+
+func getLineStarts(str string) []int {
+	starts := []int{0}
+	for i := 0; i < len(str); i++ {
+		switch str[i] {
+		case '\r':
+			if i+1 < len(str) && str[i+1] == '\n' {
+				i++
+			}
+			starts = append(starts, i+1)
+		case '\n':
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+func getRuneLength(r rune, unit CharacterUnit) int {
+	if unit == CharacterUnitUTF16 && r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+func countCharacters(str string, unit CharacterUnit) int {
+	if unit == CharacterUnitByte {
+		return len(str)
+	}
+
+	count := 0
+	for _, r := range str {
+		count += getRuneLength(r, unit)
+	}
+	return count
+}
+
+func getCharactersLength(str string, characters int, unit CharacterUnit) int {
+	if unit == CharacterUnitByte {
+		if characters > len(str) {
+			return len(str)
+		}
+		return characters
+	}
+
+	count := 0
+	for i, r := range str {
+		count += getRuneLength(r, unit)
+		if count > characters {
+			return i
+		}
+	}
+	return len(str)
+}
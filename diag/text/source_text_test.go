@@ -0,0 +1,78 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SourceText_Lines(t *testing.T) {
+	src := text.NewSourceText("a\nbc\r\ndef\rg")
+	assert.Equal(t, 4, src.LineCount())
+	assert.Equal(t, "a", src.GetLineText(0))
+	assert.Equal(t, "bc", src.GetLineText(1))
+	assert.Equal(t, "def", src.GetLineText(2))
+	assert.Equal(t, "g", src.GetLineText(3))
+	assert.Equal(t, text.NewTextSpan(2, 4), src.GetLineSpanIncludingLineBreak(1))
+
+	src = text.NewSourceText("a\n")
+	assert.Equal(t, 2, src.LineCount())
+	assert.Equal(t, "", src.GetLineText(1))
+	assert.Panics(t, func() { src.GetLineSpan(2) })
+}
+
+func Test_SourceText_GetLineFromPosition(t *testing.T) {
+	src := text.NewSourceText("ab\ncd\r\nef")
+	assert.Equal(t, 0, src.GetLineFromPosition(0))
+	assert.Equal(t, 0, src.GetLineFromPosition(2))
+	assert.Equal(t, 1, src.GetLineFromPosition(3))
+	assert.Equal(t, 1, src.GetLineFromPosition(6))
+	assert.Equal(t, 2, src.GetLineFromPosition(7))
+	assert.Equal(t, 2, src.GetLineFromPosition(9))
+	assert.Panics(t, func() { src.GetLineFromPosition(10) })
+}
+
+func Test_SourceText_GetLinePosition(t *testing.T) {
+	// "é" is 2 bytes, 1 rune, 1 utf-16 unit; "𝄞" is 4 bytes, 1 rune, 2 utf-16 units
+	src := text.NewSourceText("x\né𝄞y\n")
+	pos := 2 + len("é𝄞")
+	assert.Equal(t, text.NewLinePosition(1, 6), src.GetLinePosition(pos, text.CharacterUnitByte))
+	assert.Equal(t, text.NewLinePosition(1, 2), src.GetLinePosition(pos, text.CharacterUnitRune))
+	assert.Equal(t, text.NewLinePosition(1, 3), src.GetLinePosition(pos, text.CharacterUnitUTF16))
+	assert.Equal(t, text.NewLinePosition(2, 0), src.GetLinePosition(src.Length(), text.CharacterUnitUTF16))
+}
+
+func Test_SourceText_GetPosition(t *testing.T) {
+	src := text.NewSourceText("x\né𝄞y\n")
+	pos := 2 + len("é𝄞")
+	assert.Equal(t, pos, src.GetPosition(text.NewLinePosition(1, 6), text.CharacterUnitByte))
+	assert.Equal(t, pos, src.GetPosition(text.NewLinePosition(1, 2), text.CharacterUnitRune))
+	assert.Equal(t, pos, src.GetPosition(text.NewLinePosition(1, 3), text.CharacterUnitUTF16))
+
+	// character in the middle of a surrogate pair maps to the rune start
+	assert.Equal(t, 2+len("é"), src.GetPosition(text.NewLinePosition(1, 2), text.CharacterUnitUTF16))
+
+	// character beyond the line end is clamped
+	assert.Equal(t, 1, src.GetPosition(text.NewLinePosition(0, 10), text.CharacterUnitRune))
+	assert.Panics(t, func() { src.GetPosition(text.NewLinePosition(3, 0), text.CharacterUnitByte) })
+}
+
+func Test_SourceText_LinePositionSpan(t *testing.T) {
+	src := text.NewSourceText("package main\n\nvar s = \"日本\"\n")
+	span := text.NewTextSpan(22, 8)
+	assert.Equal(t, `"日本"`, src.GetSubText(span))
+
+	units := []text.CharacterUnit{
+		text.CharacterUnitByte,
+		text.CharacterUnitRune,
+		text.CharacterUnitUTF16,
+	}
+	ends := []int{16, 12, 12}
+	for i, unit := range units {
+		lineSpan := src.GetLinePositionSpan(span, unit)
+		assert.Equal(t, text.NewLinePosition(2, 8), lineSpan.Start, unit.String())
+		assert.Equal(t, text.NewLinePosition(2, ends[i]), lineSpan.End, unit.String())
+		assert.Equal(t, span, src.GetTextSpan(lineSpan, unit), unit.String())
+	}
+}
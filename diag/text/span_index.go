@@ -0,0 +1,123 @@
+package text
+
+import (
+	"sort"
+)
+
+// SpanIndexEntry represents span stored in the span index with its associated value
+type SpanIndexEntry struct {
+	Span  TextSpan
+	Value interface{}
+}
+
+// SpanIndex is an interval tree which finds spans overlapping, intersecting or containing the given span
+// in logarithmic time. Entries are kept in an array sorted by span, the tree is implicit in the array:
+// the root of a range is its middle element and each element stores the max end of its subtree.
+// The index is not safe for concurrent use.
+type SpanIndex struct {
+	entries []SpanIndexEntry
+	maxEnds []int
+	sorted  bool
+}
+
+// NewSpanIndex creates new span index
+func NewSpanIndex() *SpanIndex {
+	return &SpanIndex{sorted: true}
+}
+
+// Add adds span with the associated value to the index
+func (i *SpanIndex) Add(span TextSpan, value interface{}) {
+	i.entries = append(i.entries, SpanIndexEntry{Span: span, Value: value})
+	i.sorted = false
+}
+
+// Len returns number of spans in the index
+func (i *SpanIndex) Len() int {
+	return len(i.entries)
+}
+
+// GetOverlapping returns entries which spans have non empty overlap with the given span
+func (i *SpanIndex) GetOverlapping(span TextSpan) []SpanIndexEntry {
+	// start < span.End() && end > span.Start()
+	return i.find(span.End()-1, span.Start()+1, func(s TextSpan) bool {
+		return s.OverlapsWith(span)
+	})
+}
+
+// GetIntersecting returns entries which spans intersect with the given span
+func (i *SpanIndex) GetIntersecting(span TextSpan) []SpanIndexEntry {
+	return i.find(span.End(), span.Start(), func(s TextSpan) bool {
+		return s.IntersectsWith(span)
+	})
+}
+
+// GetContaining returns entries which spans contain the given span
+func (i *SpanIndex) GetContaining(span TextSpan) []SpanIndexEntry {
+	return i.find(span.Start(), span.End(), func(s TextSpan) bool {
+		return s.ContainsSpan(span)
+	})
+}
+
+// GetContainingPos returns entries which spans contain the given position
+func (i *SpanIndex) GetContainingPos(pos int) []SpanIndexEntry {
+	return i.find(pos, pos+1, func(s TextSpan) bool {
+		return s.ContainsPos(pos)
+	})
+}
+
+// find returns entries with start <= maxStart and end >= minEnd which satisfy the predicate
+func (i *SpanIndex) find(maxStart, minEnd int, predicate func(TextSpan) bool) []SpanIndexEntry {
+	i.build()
+	r := []SpanIndexEntry{}
+	return i.findRec(r, 0, len(i.entries), maxStart, minEnd, predicate)
+}
+
+func (i *SpanIndex) findRec(r []SpanIndexEntry, lo, hi int, maxStart, minEnd int, predicate func(TextSpan) bool) []SpanIndexEntry {
+	if lo >= hi {
+		return r
+	}
+
+	mid := (lo + hi) / 2
+	if i.maxEnds[mid] < minEnd {
+		return r
+	}
+
+	r = i.findRec(r, lo, mid, maxStart, minEnd, predicate)
+
+	entry := i.entries[mid]
+	if entry.Span.Start() > maxStart {
+		return r
+	}
+
+	if predicate(entry.Span) {
+		r = append(r, entry)
+	}
+
+	return i.findRec(r, mid+1, hi, maxStart, minEnd, predicate)
+}
+
+func (i *SpanIndex) build() {
+	if i.sorted {
+		return
+	}
+
+	sort.SliceStable(i.entries, func(a, b int) bool {
+		return i.entries[a].Span.CompareTo(i.entries[b].Span) < 0
+	})
+	i.maxEnds = make([]int, len(i.entries))
+	i.buildRec(0, len(i.entries))
+	i.sorted = true
+}
+
+func (i *SpanIndex) buildRec(lo, hi int) int {
+	if lo >= hi {
+		return -1
+	}
+
+	mid := (lo + hi) / 2
+	maxEnd := i.entries[mid].Span.End()
+	maxEnd = maxInt(maxEnd, i.buildRec(lo, mid))
+	maxEnd = maxInt(maxEnd, i.buildRec(mid+1, hi))
+	i.maxEnds[mid] = maxEnd
+	return maxEnd
+}
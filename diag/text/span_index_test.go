@@ -0,0 +1,80 @@
+package text_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func getSpanIndexSpans(entries []text.SpanIndexEntry) []text.TextSpan {
+	r := []text.TextSpan{}
+	for _, e := range entries {
+		r = append(r, e.Span)
+	}
+	return r
+}
+
+func filterSpans(spans []text.TextSpan, predicate func(text.TextSpan) bool) []text.TextSpan {
+	r := []text.TextSpan{}
+	for _, s := range spans {
+		if predicate(s) {
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+func Test_SpanIndex_Queries(t *testing.T) {
+	index := text.NewSpanIndex()
+	index.Add(text.NewTextSpan(10, 5), "b")
+	index.Add(text.NewTextSpan(0, 20), "a")
+	index.Add(text.NewTextSpan(12, 0), "c")
+	index.Add(text.NewTextSpan(16, 2), "d")
+	assert.Equal(t, 4, index.Len())
+
+	r := index.GetOverlapping(text.NewTextSpan(14, 2))
+	assert.Equal(t, []text.TextSpan{text.NewTextSpan(0, 20), text.NewTextSpan(10, 5)}, getSpanIndexSpans(r))
+	assert.Equal(t, "a", r[0].Value)
+	assert.Equal(t, "b", r[1].Value)
+
+	r = index.GetIntersecting(text.NewTextSpan(12, 0))
+	assert.Equal(t, []text.TextSpan{
+		text.NewTextSpan(0, 20),
+		text.NewTextSpan(10, 5),
+		text.NewTextSpan(12, 0),
+	}, getSpanIndexSpans(r))
+
+	r = index.GetContaining(text.NewTextSpan(16, 1))
+	assert.Equal(t, []text.TextSpan{text.NewTextSpan(0, 20), text.NewTextSpan(16, 2)}, getSpanIndexSpans(r))
+
+	r = index.GetContainingPos(15)
+	assert.Equal(t, []text.TextSpan{text.NewTextSpan(0, 20)}, getSpanIndexSpans(r))
+
+	assert.Empty(t, index.GetOverlapping(text.NewTextSpan(30, 5)))
+	assert.Empty(t, text.NewSpanIndex().GetContainingPos(0))
+}
+
+func Test_SpanIndex_Random(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	index := text.NewSpanIndex()
+	spans := []text.TextSpan{}
+	for i := 0; i < 500; i++ {
+		s := text.NewTextSpan(rnd.Intn(1000), rnd.Intn(50))
+		spans = append(spans, s)
+		index.Add(s, i)
+	}
+
+	for i := 0; i < 200; i++ {
+		q := text.NewTextSpan(rnd.Intn(1100), rnd.Intn(20))
+		assert.ElementsMatch(t, filterSpans(spans, q.OverlapsWith), getSpanIndexSpans(index.GetOverlapping(q)))
+		assert.ElementsMatch(t, filterSpans(spans, q.IntersectsWith), getSpanIndexSpans(index.GetIntersecting(q)))
+		assert.ElementsMatch(t, filterSpans(spans, func(s text.TextSpan) bool {
+			return s.ContainsSpan(q)
+		}), getSpanIndexSpans(index.GetContaining(q)))
+		assert.ElementsMatch(t, filterSpans(spans, func(s text.TextSpan) bool {
+			return s.ContainsPos(q.Start())
+		}), getSpanIndexSpans(index.GetContainingPos(q.Start())))
+	}
+}
@@ -0,0 +1,102 @@
+package text
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TextChange represents replacement of the text span with the new text
+type TextChange struct {
+	Span    TextSpan
+	NewText string
+}
+
+// NewTextChange creates new text change
+func NewTextChange(span TextSpan, newText string) TextChange {
+	return TextChange{Span: span, NewText: newText}
+}
+
+// String returns string representation of the text change
+func (c TextChange) String() string {
+	return fmt.Sprintf("%v -> %q", c.Span, c.NewText)
+}
+
+// TextChangeRange represents span in the old text and length of the text that replaced it
+type TextChangeRange struct {
+	Span      TextSpan
+	NewLength int
+}
+
+// NewTextChangeRange creates new text change range
+func NewTextChangeRange(span TextSpan, newLength int) TextChangeRange {
+	if newLength < 0 {
+		panic("newLength is negative!")
+	}
+
+	return TextChangeRange{Span: span, NewLength: newLength}
+}
+
+// String returns string representation of the text change range
+func (r TextChangeRange) String() string {
+	return fmt.Sprintf("%v -> %d", r.Span, r.NewLength)
+}
+
+// NewSpan returns span of the new text in the changed text
+func (r TextChangeRange) NewSpan() TextSpan {
+	return NewTextSpan(r.Span.Start(), r.NewLength)
+}
+
+// OverlappingChangesError is returned when text changes overlap
+type OverlappingChangesError struct {
+	Change1 TextChange
+	Change2 TextChange
+}
+
+func (e *OverlappingChangesError) Error() string {
+	return fmt.Sprintf("text change %v overlaps with %v", e.Change2, e.Change1)
+}
+
+// OutOfRangeChangeError is returned when text change span is outside of the text
+type OutOfRangeChangeError struct {
+	Change TextChange
+	Length int
+}
+
+func (e *OutOfRangeChangeError) Error() string {
+	return fmt.Sprintf("text change %v is outside of the text of length %d", e.Change, e.Length)
+}
+
+// SortTextChanges returns copy of the changes sorted by span
+func SortTextChanges(changes []TextChange) []TextChange {
+	sorted := make([]TextChange, len(changes))
+	copy(sorted, changes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Span.CompareTo(sorted[j].Span) < 0
+	})
+	return sorted
+}
+
+// ValidateTextChanges checks that changes are inside the text of the given length and do not overlap
+func ValidateTextChanges(changes []TextChange, length int) error {
+	sorted := SortTextChanges(changes)
+	for i, c := range sorted {
+		if !c.Span.IsValid() || c.Span.End() > length {
+			return &OutOfRangeChangeError{Change: c, Length: length}
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		prev := sorted[i-1]
+		if c.Span.Start() < prev.Span.End() {
+			return &OverlappingChangesError{Change1: prev, Change2: c}
+		}
+
+		// two inserts at the same position have no defined order
+		if c.Span.Start() == prev.Span.Start() && c.Span.IsEmpty() && prev.Span.IsEmpty() {
+			return &OverlappingChangesError{Change1: prev, Change2: c}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package text_test
+
+import (
+	"testing"
+
+	"github.com/a6cexz/goanalyzer/diag/text"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TextChange_String(t *testing.T) {
+	c := text.NewTextChange(text.NewTextSpan(1, 2), "ab")
+	assert.Equal(t, `[1..3) -> "ab"`, c.String())
+}
+
+func Test_TextChange_ValidateTextChanges(t *testing.T) {
+	c1 := text.NewTextChange(text.NewTextSpan(0, 2), "a")
+	c2 := text.NewTextChange(text.NewTextSpan(2, 2), "b")
+	c3 := text.NewTextChange(text.NewTextSpan(1, 2), "c")
+	c4 := text.NewTextChange(text.NewTextSpan(2, 0), "d")
+	c5 := text.NewTextChange(text.NewTextSpan(8, 4), "e")
+
+	assert.NoError(t, text.ValidateTextChanges([]text.TextChange{c2, c1}, 10))
+	assert.NoError(t, text.ValidateTextChanges([]text.TextChange{c1, c4, c2}, 10))
+
+	err := text.ValidateTextChanges([]text.TextChange{c1, c3}, 10)
+	assert.IsType(t, &text.OverlappingChangesError{}, err)
+
+	err = text.ValidateTextChanges([]text.TextChange{c4, c4}, 10)
+	assert.IsType(t, &text.OverlappingChangesError{}, err)
+
+	err = text.ValidateTextChanges([]text.TextChange{c5}, 10)
+	assert.IsType(t, &text.OutOfRangeChangeError{}, err)
+}
+
+func Test_SourceText_WithChanges(t *testing.T) {
+	src := text.NewSourceText("var a = 10\nvar b = 20\n")
+	changed, err := src.WithChanges(
+		text.NewTextChange(text.NewTextSpan(19, 2), "200"),
+		text.NewTextChange(text.NewTextSpan(4, 1), "x"),
+		text.NewTextChange(text.NewTextSpan(0, 0), "// c\n"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "// c\nvar x = 10\nvar b = 200\n", changed.String())
+	assert.Equal(t, 4, changed.LineCount())
+	assert.Equal(t, "var b = 200", changed.GetLineText(2))
+	assert.Equal(t, "var a = 10\nvar b = 20\n", src.String())
+
+	_, err = src.WithChanges(
+		text.NewTextChange(text.NewTextSpan(0, 5), ""),
+		text.NewTextChange(text.NewTextSpan(4, 1), "x"),
+	)
+	assert.Error(t, err)
+
+	same, err := src.WithChanges()
+	assert.NoError(t, err)
+	assert.True(t, same == src)
+}
+
+func Test_SourceText_GetChangeRanges(t *testing.T) {
+	src := text.NewSourceText("0123456789")
+	v1, err := src.WithChanges(
+		text.NewTextChange(text.NewTextSpan(1, 2), ""),
+		text.NewTextChange(text.NewTextSpan(5, 0), "abc"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "034abc56789", v1.String())
+	assert.Equal(t, []text.TextChangeRange{
+		text.NewTextChangeRange(text.NewTextSpan(1, 2), 0),
+		text.NewTextChangeRange(text.NewTextSpan(5, 0), 3),
+	}, v1.GetChangeRanges(src))
+	assert.Empty(t, v1.GetChangeRanges(v1))
+
+	v2, err := v1.WithChanges(text.NewTextChange(text.NewTextSpan(9, 1), "XY"))
+	assert.NoError(t, err)
+	assert.Equal(t, "034abc567XY9", v2.String())
+
+	ranges := v2.GetChangeRanges(src)
+	assert.Equal(t, []text.TextChangeRange{
+		text.NewTextChangeRange(text.NewTextSpan(1, 8), 10),
+	}, ranges)
+	r := ranges[0]
+	assert.Equal(t, "12345678", src.GetSubText(r.Span))
+	assert.Equal(t, "34abc567XY", v2.GetSubText(r.NewSpan()))
+
+	other := text.NewSourceText("other")
+	assert.Equal(t, []text.TextChangeRange{
+		text.NewTextChangeRange(text.NewTextSpan(0, 5), 12),
+	}, v2.GetChangeRanges(other))
+}
@@ -75,9 +75,9 @@ func (s TextSpan) CompareTo(span TextSpan) int {
 	return s.length - span.length
 }
 
-// ContainsPos checks if position is insider the span
+// ContainsPos checks if position is inside the span
 func (s TextSpan) ContainsPos(pos int) bool {
-	return pos-s.start < s.length
+	return pos >= s.start && pos < s.End()
 }
 
 // ContainsSpan checks if span contains another span
@@ -89,3 +89,59 @@ func (s TextSpan) ContainsSpan(span TextSpan) bool {
 func (s TextSpan) Equals(span TextSpan) bool {
 	return s.start == span.start && s.length == span.length
 }
+
+// OverlapsWith checks if span has non empty overlap with another span
+func (s TextSpan) OverlapsWith(span TextSpan) bool {
+	return maxInt(s.start, span.start) < minInt(s.End(), span.End())
+}
+
+// Overlap returns non empty overlap of the spans, ok is false if spans do not overlap
+func (s TextSpan) Overlap(span TextSpan) (overlap TextSpan, ok bool) {
+	start := maxInt(s.start, span.start)
+	end := minInt(s.End(), span.End())
+	if start < end {
+		return NewTextSpanFromBounds(start, end), true
+	}
+	return TextSpan{}, false
+}
+
+// IntersectsWith checks if span intersects with another span, spans which touch each other intersect
+func (s TextSpan) IntersectsWith(span TextSpan) bool {
+	return span.start <= s.End() && span.End() >= s.start
+}
+
+// IntersectsWithPos checks if position is inside the span or at its end
+func (s TextSpan) IntersectsWithPos(pos int) bool {
+	return pos >= s.start && pos <= s.End()
+}
+
+// Intersection returns intersection of the spans, ok is false if spans do not intersect
+func (s TextSpan) Intersection(span TextSpan) (intersection TextSpan, ok bool) {
+	start := maxInt(s.start, span.start)
+	end := minInt(s.End(), span.End())
+	if start <= end {
+		return NewTextSpanFromBounds(start, end), true
+	}
+	return TextSpan{}, false
+}
+
+// Union returns the smallest span which contains both spans
+func (s TextSpan) Union(span TextSpan) TextSpan {
+	start := minInt(s.start, span.start)
+	end := maxInt(s.End(), span.End())
+	return NewTextSpanFromBounds(start, end)
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
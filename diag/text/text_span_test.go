@@ -55,7 +55,7 @@ func Test_TextSpan_CompareTo(t *testing.T) {
 
 func Test_TextSpan_ContainsPos(t *testing.T) {
 	s := text.NewTextSpan(0, 4)
-	assert.True(t, s.ContainsPos(-1))
+	assert.False(t, s.ContainsPos(-1))
 	assert.True(t, s.ContainsPos(0))
 	assert.True(t, s.ContainsPos(1))
 	assert.True(t, s.ContainsPos(2))
@@ -63,6 +63,16 @@ func Test_TextSpan_ContainsPos(t *testing.T) {
 	assert.False(t, s.ContainsPos(4))
 	assert.False(t, s.ContainsPos(5))
 	assert.False(t, s.ContainsPos(6))
+
+	s = text.NewTextSpan(2, 2)
+	assert.False(t, s.ContainsPos(0))
+	assert.False(t, s.ContainsPos(1))
+	assert.True(t, s.ContainsPos(2))
+	assert.True(t, s.ContainsPos(3))
+	assert.False(t, s.ContainsPos(4))
+
+	s = text.NewTextSpan(2, 0)
+	assert.False(t, s.ContainsPos(2))
 }
 
 func Test_TextSpan_ContainsSpan(t *testing.T) {
@@ -85,3 +95,62 @@ func Test_TextSpan_Equals(t *testing.T) {
 	assert.True(t, s1.Equals(s2))
 	assert.False(t, s1.Equals(s3))
 }
+
+func Test_TextSpan_OverlapsWith_Overlap(t *testing.T) {
+	s := text.NewTextSpan(2, 4)
+	cases := []struct {
+		span    text.TextSpan
+		overlap bool
+		result  text.TextSpan
+	}{
+		{text.NewTextSpan(0, 2), false, text.TextSpan{}},
+		{text.NewTextSpan(0, 3), true, text.NewTextSpan(2, 1)},
+		{text.NewTextSpan(3, 1), true, text.NewTextSpan(3, 1)},
+		{text.NewTextSpan(5, 5), true, text.NewTextSpan(5, 1)},
+		{text.NewTextSpan(6, 1), false, text.TextSpan{}},
+		{text.NewTextSpan(3, 0), false, text.TextSpan{}},
+		{text.NewTextSpan(0, 10), true, text.NewTextSpan(2, 4)},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.overlap, s.OverlapsWith(c.span), c.span.String())
+		assert.Equal(t, c.overlap, c.span.OverlapsWith(s), c.span.String())
+		r, ok := s.Overlap(c.span)
+		assert.Equal(t, c.overlap, ok, c.span.String())
+		assert.Equal(t, c.result, r, c.span.String())
+	}
+}
+
+func Test_TextSpan_IntersectsWith_Intersection(t *testing.T) {
+	s := text.NewTextSpan(2, 4)
+	cases := []struct {
+		span       text.TextSpan
+		intersects bool
+		result     text.TextSpan
+	}{
+		{text.NewTextSpan(0, 1), false, text.TextSpan{}},
+		{text.NewTextSpan(0, 2), true, text.NewTextSpan(2, 0)},
+		{text.NewTextSpan(3, 0), true, text.NewTextSpan(3, 0)},
+		{text.NewTextSpan(4, 4), true, text.NewTextSpan(4, 2)},
+		{text.NewTextSpan(6, 1), true, text.NewTextSpan(6, 0)},
+		{text.NewTextSpan(7, 1), false, text.TextSpan{}},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.intersects, s.IntersectsWith(c.span), c.span.String())
+		assert.Equal(t, c.intersects, c.span.IntersectsWith(s), c.span.String())
+		r, ok := s.Intersection(c.span)
+		assert.Equal(t, c.intersects, ok, c.span.String())
+		assert.Equal(t, c.result, r, c.span.String())
+	}
+
+	assert.True(t, s.IntersectsWithPos(2))
+	assert.True(t, s.IntersectsWithPos(6))
+	assert.False(t, s.IntersectsWithPos(1))
+	assert.False(t, s.IntersectsWithPos(7))
+}
+
+func Test_TextSpan_Union(t *testing.T) {
+	s1 := text.NewTextSpan(2, 4)
+	assert.Equal(t, text.NewTextSpan(0, 6), s1.Union(text.NewTextSpan(0, 1)))
+	assert.Equal(t, text.NewTextSpan(2, 8), s1.Union(text.NewTextSpan(8, 2)))
+	assert.Equal(t, s1, s1.Union(text.NewTextSpan(3, 1)))
+}
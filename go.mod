@@ -1,8 +1,16 @@
 module github.com/a6cexz/goanalyzer
 
-go 1.15
+go 1.22.0
 
 require (
 	github.com/stretchr/testify v1.7.0
-	golang.org/x/tools v0.1.0
+	golang.org/x/tools v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
 )
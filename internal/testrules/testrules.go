@@ -0,0 +1,36 @@
+// Package testrules contains rules shared by tests of the analysis, bridge and cli packages
+package testrules
+
+import (
+	"go/ast"
+
+	"github.com/a6cexz/goanalyzer/diag"
+	"github.com/a6cexz/goanalyzer/diag/analysis"
+	"github.com/a6cexz/goanalyzer/diag/syntax/syntaxkind"
+	"github.com/a6cexz/goanalyzer/diag/text"
+)
+
+// PrintlnRule reports println calls with the call as additional location and suggests to replace them with print
+type PrintlnRule struct{}
+
+// Metadata returns metadata of the rule
+func (r PrintlnRule) Metadata() analysis.RuleInfo {
+	return analysis.RuleInfo{ID: "GA9000", Name: "no-println", Title: "Avoid println", DefaultSeverity: diag.SeverityWarning}
+}
+
+// Initialize registers the call action of the rule
+func (r PrintlnRule) Initialize(ctx *analysis.RuleContext) {
+	ctx.RegisterNodeAction(func(ctx *analysis.NodeContext) {
+		call := ctx.Node.GetAstNode().(*ast.CallExpr)
+		ident, ok := call.Fun.(*ast.Ident)
+		if !ok || ident.Name != "println" {
+			return
+		}
+
+		fun := ctx.Node.GetElements()[0]
+		d := ctx.NewDiagnostic(fun, "avoid %s", ident.Name)
+		d.AdditionalLocations = append(d.AdditionalLocations, diag.NewLabeledLocation(d.Location.Path, ctx.Document.GetSpan(ctx.Node), "call"))
+		d.Fixes = []diag.CodeFix{diag.NewCodeFix("Use print", text.NewTextChange(ctx.Document.GetSpan(fun), "print"))}
+		ctx.Report(d)
+	}, syntaxkind.AstCallExpr)
+}